@@ -0,0 +1,31 @@
+package statter
+
+// AggregateReporter represents a stats reporter that handles the stats
+// aggregated locally from histograms, timings and distributions, such as
+// the latency_count and latency_sum stats of a latency histogram.
+//
+// The stats are reported to it with the name of the metric they are
+// aggregated from, so that it may handle them as that metric. Other
+// reporters receive them as counters and gauges.
+type AggregateReporter interface {
+	AggregateCounter(metric, name string, v int64, tags [][2]string)
+	AggregateGauge(metric, name string, v float64, tags [][2]string)
+}
+
+// reportAggregateCounter reports a counter aggregated from metric.
+func reportAggregateCounter(r Reporter, metric, name string, v int64, tags [][2]string) {
+	if ar, ok := r.(AggregateReporter); ok {
+		ar.AggregateCounter(metric, name, v, tags)
+		return
+	}
+	r.Counter(name, v, tags)
+}
+
+// reportAggregateGauge reports a gauge aggregated from metric.
+func reportAggregateGauge(r Reporter, metric, name string, v float64, tags [][2]string) {
+	if ar, ok := r.(AggregateReporter); ok {
+		ar.AggregateGauge(metric, name, v, tags)
+		return
+	}
+	r.Gauge(name, v, tags)
+}
@@ -0,0 +1,55 @@
+package statter_test
+
+import (
+	"testing"
+
+	"github.com/hamba/statter/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateReporter(t *testing.T) {
+	r := &aggregateReporter{}
+	m := &mockSimpleReporter{}
+	m.On("Counter", "latency_count", int64(1), [][2]string{}).Once()
+	m.On("Gauge", mock.Anything, mock.Anything, [][2]string{}).Times(5)
+	m.On("Counter", "size_bucket", int64(1), mock.Anything).Twice()
+	m.On("Counter", "size_count", int64(1), [][2]string{}).Once()
+	stats := statter.New(statter.MultiReporter(r, m), 0, statter.WithPercentiles(nil), statter.WithDistributionBuckets([]float64{5}))
+
+	stats.Histogram("latency").Observe(1)
+	stats.Distribution("size").Observe(1)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"latency/latency_count",
+		"latency/latency_sum",
+		"latency/latency_mean",
+		"latency/latency_stddev",
+		"latency/latency_min",
+		"latency/latency_max",
+		"size/size_bucket",
+		"size/size_bucket",
+		"size/size_count",
+	}, r.stats)
+	m.AssertExpectations(t)
+}
+
+type aggregateReporter struct {
+	stats []string
+}
+
+func (r *aggregateReporter) Counter(string, int64, [][2]string) {}
+
+func (r *aggregateReporter) Gauge(string, float64, [][2]string) {}
+
+func (r *aggregateReporter) AggregateCounter(metric, name string, _ int64, _ [][2]string) {
+	r.stats = append(r.stats, metric+"/"+name)
+}
+
+func (r *aggregateReporter) AggregateGauge(metric, name string, _ float64, _ [][2]string) {
+	r.stats = append(r.stats, metric+"/"+name)
+}
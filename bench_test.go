@@ -1,6 +1,7 @@
 package statter_test
 
 import (
+	"strconv"
 	"testing"
 	"time"
 
@@ -24,6 +25,22 @@ func BenchmarkStatter_Counter(b *testing.B) {
 	_ = s.Close()
 }
 
+func BenchmarkStatter_CounterVec(b *testing.B) {
+	s := statter.New(discardReporter{}, time.Second)
+	v := s.CounterVec("test", "test")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			v.With("test").Inc(1)
+		}
+	})
+
+	b.StopTimer()
+	_ = s.Close()
+}
+
 func BenchmarkStatter_Gauge(b *testing.B) {
 	s := statter.New(discardReporter{}, time.Second)
 
@@ -84,6 +101,82 @@ func BenchmarkStatter_Timing(b *testing.B) {
 	_ = s.Close()
 }
 
+func BenchmarkTiming_SampleRate(b *testing.B) {
+	s := statter.New(discardReporter{}, time.Second)
+	t := s.Options(statter.SampleRate(0.1)).Timing("test", tags.Str("test", "test"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			t.Observe(12340 * time.Microsecond)
+		}
+	})
+
+	b.StopTimer()
+	_ = s.Close()
+}
+
+// BenchmarkHistogram_Shards measures parallel observation throughput by
+// number of aggregation shards. Run with -cpu 1,2,4,8 to compare scaling.
+func BenchmarkHistogram_Shards(b *testing.B) {
+	for _, n := range []int{1, 4, 16} {
+		b.Run("shards="+strconv.Itoa(n), func(b *testing.B) {
+			s := statter.New(discardReporter{}, time.Second, statter.WithAggregationShards(n))
+			h := s.Histogram("test", tags.Str("test", "test"))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					h.Observe(12.34)
+				}
+			})
+
+			b.StopTimer()
+			_ = s.Close()
+		})
+	}
+}
+
+// BenchmarkTiming_Shards measures parallel observation throughput by
+// number of aggregation shards. Run with -cpu 1,2,4,8 to compare scaling.
+func BenchmarkTiming_Shards(b *testing.B) {
+	for _, n := range []int{1, 4, 16} {
+		b.Run("shards="+strconv.Itoa(n), func(b *testing.B) {
+			s := statter.New(discardReporter{}, time.Second, statter.WithAggregationShards(n))
+			t := s.Timing("test", tags.Str("test", "test"))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					t.Observe(12340 * time.Microsecond)
+				}
+			})
+
+			b.StopTimer()
+			_ = s.Close()
+		})
+	}
+}
+
+func BenchmarkTiming_Stopwatch(b *testing.B) {
+	s := statter.New(discardReporter{}, time.Second)
+	t := s.Timing("test", tags.Str("test", "test"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			t.Start().Stop()
+		}
+	})
+
+	b.StopTimer()
+	_ = s.Close()
+}
+
 func BenchmarkStatter_PrometheusHistogram(b *testing.B) {
 	s := statter.New(prometheus.New("test"), time.Second)
 
@@ -119,3 +212,18 @@ type discardReporter struct{}
 func (r discardReporter) Counter(string, int64, [][2]string) {}
 
 func (r discardReporter) Gauge(string, float64, [][2]string) {}
+
+func BenchmarkStatter_CounterTagPolicy(b *testing.B) {
+	s := statter.New(discardReporter{}, time.Second, statter.WithTagPolicy(statter.TagPolicy{Lowercase: true}))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Counter("test", tags.Str("test", "TEST")).Inc(1)
+		}
+	})
+
+	b.StopTimer()
+	_ = s.Close()
+}
@@ -0,0 +1,269 @@
+package statter
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// HasGaugeFunc determines if the gauge function exists.
+func (s *Statter) HasGaugeFunc(name string, tags ...Tag) bool {
+	k := s.seriesKey(name, tags)
+
+	_, ok := s.reg.gaugeFuncs.Load(k.String())
+
+	k.Release()
+
+	return ok
+}
+
+// GaugeFunc registers a gauge whose value is returned by fn. The function
+// is called on each report, or by the reporter when it implements
+// [GaugeFuncReporter], such as when metrics are scraped. The gauge function
+// is created on the first call; subsequent calls with identical name and
+// tags return the same instance and do not replace its function.
+//
+// The function must be safe for concurrent use. A function that panics
+// or runs longer than the callback timeout is skipped for that report
+// and a [*CallbackError] is passed to the error handler. The function is
+// never called again while a previous call is still running.
+func (s *Statter) GaugeFunc(name string, fn func() float64, tags ...Tag) *GaugeFunc {
+	return s.gaugeFunc(name, fn, tags, metricOptions{})
+}
+
+func (s *Statter) gaugeFunc(name string, fn func() float64, tags []Tag, opts metricOptions) *GaugeFunc {
+	if s.reg.closed.Load() {
+		return s.reg.nopGaugeFunc
+	}
+
+	k := s.key(name, tags)
+
+	g, ok := loadSeries(s.reg, &s.reg.gaugeFuncs, k.String())
+	if !ok {
+		var created bool
+		g, created = newSeries(s, &s.reg.gaugeFuncs, k, name, tags, func(sr series) *GaugeFunc {
+			s.reg.describe(sr.name, opts.meta)
+			return &GaugeFunc{
+				name:     sr.name,
+				tags:     sr.tags,
+				key:      sr.key,
+				reg:      s.reg,
+				meta:     opts.meta,
+				overflow: sr.overflow,
+				fn:       fn,
+			}
+		})
+		if created && s.reg.gfr != nil {
+			s.reg.gfr.GaugeFunc(g.name, g.scrape, g.tags)
+		}
+	}
+
+	k.Release()
+
+	return g
+}
+
+// GaugeFunc implements a gauge whose value is returned by a function.
+type GaugeFunc struct {
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
+
+	fn      func() float64
+	running atomic.Bool
+	last    atomic.Uint64
+}
+
+// Delete removes the gauge function. Delete is a no-op once the statter
+// is closed.
+func (g *GaugeFunc) Delete() {
+	if g.reg.closed.Load() {
+		return
+	}
+
+	if g.reg.gfr != nil {
+		g.reg.gfr.RemoveGaugeFunc(g.name, g.tags)
+	}
+	if rr, ok := g.reg.gfa.(RemovableReporter); ok {
+		rr.RemoveGauge(g.name, g.tags)
+	}
+	if _, ok := g.reg.gaugeFuncs.LoadAndDelete(g.key); ok {
+		g.reg.removed(g.name, g.overflow)
+	}
+}
+
+// value calls the function, returning false if the call failed.
+func (g *GaugeFunc) value() (float64, bool) {
+	v, ok := callback(g.reg, &g.running, g.name, g.tags, g.fn)
+	if ok {
+		g.last.Store(math.Float64bits(v))
+	}
+	return v, ok
+}
+
+// scrape returns the gauge value for reporters evaluating gauge
+// functions, falling back to the last value if the call failed.
+func (g *GaugeFunc) scrape() float64 {
+	if v, ok := g.value(); ok {
+		return v
+	}
+	return g.lastValue()
+}
+
+func (g *GaugeFunc) lastValue() float64 {
+	return math.Float64frombits(g.last.Load())
+}
+
+// HasCounterFunc determines if the counter function exists.
+func (s *Statter) HasCounterFunc(name string, tags ...Tag) bool {
+	k := s.seriesKey(name, tags)
+
+	_, ok := s.reg.counterFuncs.Load(k.String())
+
+	k.Release()
+
+	return ok
+}
+
+// CounterFunc registers a counter whose cumulative total is returned by fn.
+// The function is called on each report, and the increase since the
+// previous call is reported as a counter. The first call reports the full
+// total, and a total lower than the previous one is treated as a reset of
+// the source, reporting the new total. The counter function is created on
+// the first call; subsequent calls with identical name and tags return the
+// same instance and do not replace its function.
+//
+// The function must be safe for concurrent use. A function that panics
+// or runs longer than the callback timeout is skipped for that report
+// and a [*CallbackError] is passed to the error handler. The function is
+// never called again while a previous call is still running.
+func (s *Statter) CounterFunc(name string, fn func() int64, tags ...Tag) *CounterFunc {
+	return s.counterFunc(name, fn, tags, metricOptions{})
+}
+
+func (s *Statter) counterFunc(name string, fn func() int64, tags []Tag, opts metricOptions) *CounterFunc {
+	if s.reg.closed.Load() {
+		return s.reg.nopCounterFunc
+	}
+
+	k := s.key(name, tags)
+
+	c, ok := loadSeries(s.reg, &s.reg.counterFuncs, k.String())
+	if !ok {
+		c, _ = newSeries(s, &s.reg.counterFuncs, k, name, tags, func(sr series) *CounterFunc {
+			s.reg.describe(sr.name, opts.meta)
+			return &CounterFunc{
+				name:     sr.name,
+				tags:     sr.tags,
+				key:      sr.key,
+				reg:      s.reg,
+				meta:     opts.meta,
+				overflow: sr.overflow,
+				fn:       fn,
+			}
+		})
+	}
+
+	k.Release()
+
+	return c
+}
+
+// CounterFunc implements a counter whose cumulative total is returned
+// by a function.
+type CounterFunc struct {
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
+
+	fn      func() int64
+	running atomic.Bool
+	called  bool
+	last    atomic.Int64
+}
+
+// Delete removes the counter function. Delete is a no-op once the statter
+// is closed.
+func (c *CounterFunc) Delete() {
+	if c.reg.closed.Load() {
+		return
+	}
+
+	if rr, ok := c.reg.r.(RemovableReporter); ok {
+		rr.RemoveCounter(c.name, c.tags)
+	}
+	if _, ok := c.reg.counterFuncs.LoadAndDelete(c.key); ok {
+		c.reg.removed(c.name, c.overflow)
+	}
+}
+
+// delta calls the function, returning the increase of the total since
+// the previous call, or false if the call failed. It must only be called
+// while reporting.
+func (c *CounterFunc) delta() (int64, bool) {
+	v, ok := callback(c.reg, &c.running, c.name, c.tags, c.fn)
+	if !ok {
+		return 0, false
+	}
+
+	prev := c.last.Swap(v)
+	if !c.called || v < prev {
+		c.called = true
+		return v, true
+	}
+	return v - prev, true
+}
+
+type callbackResult[T any] struct {
+	v   T
+	err error
+}
+
+// callback calls fn, recovering from panics and giving up once the
+// callback timeout has passed. It returns false without calling fn
+// if a previous call is still running.
+func callback[T any](reg *registry, running *atomic.Bool, name string, tags [][2]string, fn func() T) (T, bool) {
+	var zero T
+
+	if !running.CompareAndSwap(false, true) {
+		return zero, false
+	}
+
+	res := make(chan callbackResult[T], 1)
+	go func() {
+		defer running.Store(false)
+		defer func() {
+			if rec := recover(); rec != nil {
+				res <- callbackResult[T]{err: fmt.Errorf("callback panicked: %v", rec)}
+			}
+		}()
+
+		res <- callbackResult[T]{v: fn()}
+	}()
+
+	var timeout <-chan time.Time
+	if d := reg.cfg.callbackTimeout; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case r := <-res:
+		if r.err != nil {
+			reg.handleError(&CallbackError{Name: name, Tags: tags, Err: r.err})
+			return zero, false
+		}
+		return r.v, true
+	case <-timeout:
+		reg.handleError(&CallbackError{Name: name, Tags: tags, Err: ErrCallbackTimeout})
+		return zero, false
+	}
+}
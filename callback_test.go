@@ -0,0 +1,278 @@
+package statter_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_GaugeFunc(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Gauge", "test", 1.0, [][2]string{{"tag", "test"}}).Once()
+	m.On("Gauge", "test", 2.0, [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	var n atomic.Int64
+	stats.GaugeFunc("test", func() float64 {
+		return float64(n.Add(1))
+	}, tags.Str("tag", "test"))
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_GaugeFuncReturnsIdenticalGaugeFunc(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	g := stats.GaugeFunc("test", func() float64 { return 1 }, tags.Str("tag", "test"))
+
+	assert.Same(t, g, stats.GaugeFunc("test", func() float64 { return 2 }, tags.Str("tag", "test")))
+	assert.True(t, stats.HasGaugeFunc("test", tags.Str("tag", "test")))
+	assert.False(t, stats.HasGauge("test", tags.Str("tag", "test")))
+}
+
+func TestStatter_GaugeFuncDelete(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.On("RemoveGauge", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	g := stats.GaugeFunc("test", func() float64 { return 1 }, tags.Str("tag", "test"))
+	g.Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.False(t, stats.HasGaugeFunc("test", tags.Str("tag", "test")))
+	m.AssertExpectations(t)
+}
+
+func TestStatter_GaugeFuncWithGaugeFuncReporter(t *testing.T) {
+	m := &mockGaugeFuncReporter{}
+	m.On("GaugeFunc", "test", [][2]string{{"tag", "test"}}).Once()
+	m.On("RemoveGaugeFunc", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	g := stats.GaugeFunc("test", func() float64 { return 2 }, tags.Str("tag", "test"))
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, m.fn())
+
+	g.Delete()
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_GaugeFuncWithMultiReporter(t *testing.T) {
+	pull := &mockGaugeFuncReporter{}
+	pull.On("GaugeFunc", "test", [][2]string{}).Once()
+	push := &mockSimpleReporter{}
+	push.On("Gauge", "test", 2.0, [][2]string{}).Once()
+
+	stats := statter.New(statter.MultiReporter(pull, push), 0)
+
+	stats.GaugeFunc("test", func() float64 { return 2 })
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, 2.0, pull.fn())
+	pull.AssertExpectations(t)
+	push.AssertExpectations(t)
+}
+
+func TestStatter_GaugeFuncRecoversPanics(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Gauge", "ok", 1.0, [][2]string{}).Once()
+
+	var got error
+	stats := statter.New(m, 0, statter.WithErrorHandler(func(err error) {
+		got = err
+	}))
+
+	stats.GaugeFunc("test", func() float64 { panic("test") })
+	stats.GaugeFunc("ok", func() float64 { return 1 })
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	var cbErr *statter.CallbackError
+	require.ErrorAs(t, got, &cbErr)
+	assert.Equal(t, "test", cbErr.Name)
+	assert.EqualError(t, got, "statter: callback error for test: callback panicked: test")
+	m.AssertExpectations(t)
+}
+
+func TestStatter_GaugeFuncTimesOut(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Gauge", "ok", 1.0, [][2]string{}).Once()
+
+	errs := make(chan error, 2)
+	stats := statter.New(m, 0,
+		statter.WithCallbackTimeout(10*time.Millisecond),
+		statter.WithErrorHandler(func(err error) { errs <- err }),
+	)
+
+	release := make(chan struct{})
+	defer close(release)
+	var calls atomic.Int64
+	stats.GaugeFunc("test", func() float64 {
+		calls.Add(1)
+		<-release
+		return 1
+	})
+	stats.GaugeFunc("ok", func() float64 { return 1 })
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	m.On("Gauge", "ok", 1.0, [][2]string{}).Once()
+	err = stats.Close()
+	require.NoError(t, err)
+
+	require.ErrorIs(t, <-errs, statter.ErrCallbackTimeout)
+	assert.Empty(t, errs)
+	assert.Equal(t, int64(1), calls.Load())
+	m.AssertExpectations(t)
+}
+
+func TestStatter_GaugeFuncSnapshot(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	stats.GaugeFunc("test", func() float64 { return 2 })
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	got := stats.Snapshot()
+
+	want := []statter.Series{{Name: "test", Tags: [][2]string{}, Type: statter.MetricTypeGauge, Value: 2}}
+	assert.Equal(t, want, got)
+}
+
+func TestStatter_ClosedGaugeFuncIsNoop(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	g := stats.GaugeFunc("test", func() float64 { return 1 })
+	g.Delete()
+
+	assert.False(t, stats.HasGaugeFunc("test"))
+}
+
+func TestStatter_CounterFunc(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(10), [][2]string{{"tag", "test"}}).Once()
+	m.On("Counter", "test", int64(5), [][2]string{{"tag", "test"}}).Once()
+	m.On("Counter", "test", int64(3), [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	totals := []int64{10, 15, 15, 3}
+	var i int
+	stats.CounterFunc("test", func() int64 {
+		v := totals[i]
+		i++
+		return v
+	}, tags.Str("tag", "test"))
+
+	for range 3 {
+		err := stats.Flush(t.Context())
+		require.NoError(t, err)
+	}
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_CounterFuncReturnsIdenticalCounterFunc(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	c := stats.CounterFunc("test", func() int64 { return 1 }, tags.Str("tag", "test"))
+
+	assert.Same(t, c, stats.CounterFunc("test", func() int64 { return 2 }, tags.Str("tag", "test")))
+	assert.True(t, stats.HasCounterFunc("test", tags.Str("tag", "test")))
+	assert.False(t, stats.HasCounter("test", tags.Str("tag", "test")))
+}
+
+func TestStatter_CounterFuncDelete(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.On("RemoveCounter", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	c := stats.CounterFunc("test", func() int64 { return 1 }, tags.Str("tag", "test"))
+	c.Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.False(t, stats.HasCounterFunc("test", tags.Str("tag", "test")))
+	m.AssertExpectations(t)
+}
+
+func TestStatter_CounterFuncSkipsFailedCalls(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(10), [][2]string{}).Once()
+	m.On("Counter", "test", int64(5), [][2]string{}).Once()
+
+	var got error
+	stats := statter.New(m, 0, statter.WithErrorHandler(func(err error) {
+		got = err
+	}))
+
+	var n int
+	stats.CounterFunc("test", func() int64 {
+		n++
+		if n == 2 {
+			panic("test")
+		}
+		return int64(n) * 5
+	})
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+	err = stats.Close()
+	require.NoError(t, err)
+
+	var cbErr *statter.CallbackError
+	require.ErrorAs(t, got, &cbErr)
+	m.AssertExpectations(t)
+}
+
+type mockGaugeFuncReporter struct {
+	mockSimpleReporter
+
+	fn func() float64
+}
+
+func (r *mockGaugeFuncReporter) GaugeFunc(name string, fn func() float64, tags [][2]string) {
+	r.fn = fn
+	_ = r.Called(name, tags)
+}
+
+func (r *mockGaugeFuncReporter) RemoveGaugeFunc(name string, tags [][2]string) {
+	_ = r.Called(name, tags)
+}
@@ -0,0 +1,80 @@
+package statter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithClock_DrivesReportLoop(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 3, 0, time.UTC))
+	r := newFlushReporter()
+
+	stats := statter.New(r, 10*time.Second, statter.WithClock(clock), statter.WithAlignedFlush())
+	t.Cleanup(func() { _ = stats.Close() })
+
+	clock.BlockUntilTimers(1)
+	clock.Advance(7 * time.Second)
+
+	assert.Equal(t, flush{ts: time.Date(2024, 1, 1, 0, 0, 10, 0, time.UTC), interval: 7 * time.Second}, r.next(t))
+
+	clock.BlockUntilTimers(1)
+	clock.Advance(10 * time.Second)
+
+	assert.Equal(t, flush{ts: time.Date(2024, 1, 1, 0, 0, 20, 0, time.UTC), interval: 10 * time.Second}, r.next(t))
+}
+
+func TestWithClock_ExpiresSeries(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := &mockComplexReporter{}
+	m.On("Gauge", "test", 1.23, [][2]string{{"tag", "test"}})
+	m.On("RemoveGauge", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0, statter.WithClock(clock), statter.WithSeriesTTL(time.Minute))
+
+	stats.Gauge("test", tags.Str("tag", "test")).Set(1.23)
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	clock.Advance(time.Minute)
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	assert.True(t, stats.HasGauge("test", tags.Str("tag", "test")))
+
+	clock.Advance(time.Second)
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	assert.False(t, stats.HasGauge("test", tags.Str("tag", "test")))
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestWithClock_Stopwatch(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	stats, r := statstest.NewStatter(t, statter.WithClock(clock))
+
+	sw := stats.Timing("test").Start()
+	clock.Advance(time.Second)
+	got := sw.Stop()
+
+	assert.Equal(t, time.Second, got)
+
+	_ = stats.Time("time", func() error {
+		clock.Advance(2 * time.Second)
+		return nil
+	})
+
+	r.AssertTiming(t, "test", nil, time.Second)
+	r.AssertTiming(t, "time", []statter.Tag{{statter.OutcomeTag, statter.OutcomeSuccess}}, 2*time.Second)
+}
@@ -22,7 +22,7 @@ func WithContext(ctx context.Context, tags ...Tag) context.Context {
 	if existing != nil && len(existing.tags) > 0 {
 		merged = make([]Tag, len(existing.tags), len(existing.tags)+len(tags))
 		copy(merged, existing.tags)
-		merged = mergeTags(merged, tags)
+		merged = mergeTags(merged, tags, nil)
 	} else {
 		merged = make([]Tag, len(tags))
 		copy(merged, tags)
@@ -0,0 +1,37 @@
+package statter
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	defaultStatter atomic.Pointer[Statter]
+
+	discardStatter = sync.OnceValue(func() *Statter {
+		return New(DiscardReporter, 0)
+	})
+)
+
+// Default returns the default statter, set with [SetDefault]. Until a
+// default statter is set, a statter reporting to [DiscardReporter] is
+// returned.
+//
+// Default allows shared libraries to instrument themselves without a
+// statter being passed to them. As metrics are bound to the statter that
+// created them, libraries should call Default when creating metrics
+// rather than keeping the returned statter.
+func Default() *Statter {
+	if s := defaultStatter.Load(); s != nil {
+		return s
+	}
+	return discardStatter()
+}
+
+// SetDefault atomically sets the statter returned by [Default]. A nil
+// statter restores the statter reporting to [DiscardReporter].
+//
+// Closing the default statter remains the responsibility of the caller.
+func SetDefault(s *Statter) {
+	defaultStatter.Store(s)
+}
@@ -0,0 +1,61 @@
+package statter_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	s := statter.Default()
+
+	require.NotNil(t, s)
+	assert.Equal(t, statter.DiscardReporter, s.Reporter())
+	assert.Same(t, s, statter.Default())
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() { statter.SetDefault(nil) })
+
+	stats, r := statstest.NewStatter(t)
+
+	statter.SetDefault(stats)
+
+	assert.Same(t, stats, statter.Default())
+
+	statter.Default().Counter("test").Inc(1)
+
+	r.AssertCounter(t, "test", nil, 1)
+}
+
+func TestSetDefault_NilRestoresDiscard(t *testing.T) {
+	t.Cleanup(func() { statter.SetDefault(nil) })
+
+	stats, _ := statstest.NewStatter(t)
+	statter.SetDefault(stats)
+
+	statter.SetDefault(nil)
+
+	assert.Equal(t, statter.DiscardReporter, statter.Default().Reporter())
+}
+
+func TestSetDefault_Concurrent(t *testing.T) {
+	t.Cleanup(func() { statter.SetDefault(nil) })
+
+	stats, _ := statstest.NewStatter(t)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			statter.SetDefault(stats)
+			statter.Default().Counter("test").Inc(1)
+		})
+	}
+	wg.Wait()
+
+	assert.Same(t, stats, statter.Default())
+}
@@ -0,0 +1,205 @@
+package statter
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync/atomic"
+)
+
+// DistributionBucketTag is the tag key holding the upper bound of
+// a locally aggregated distribution bucket.
+const DistributionBucketTag = "le"
+
+// DefaultDistributionBuckets are the default upper bounds of locally
+// aggregated distribution buckets.
+var DefaultDistributionBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// HasDistribution determines if the distribution exists.
+func (s *Statter) HasDistribution(name string, tags ...Tag) bool {
+	k := s.seriesKey(name, tags)
+
+	_, ok := s.reg.distributions.Load(k.String())
+
+	k.Release()
+
+	return ok
+}
+
+// Distribution returns a distribution for the given name and tags. The
+// distribution is created on the first call and the same instance is
+// returned for subsequent calls with identical name and tags.
+//
+// When the reporter implements [DistributionReporter], values are forwarded
+// to it for aggregation by the backend. Otherwise values are counted in
+// fixed buckets, see [Distribution].
+func (s *Statter) Distribution(name string, tags ...Tag) *Distribution {
+	return s.distribution(name, tags, metricOptions{})
+}
+
+func (s *Statter) distribution(name string, tags []Tag, opts metricOptions) *Distribution {
+	if s.reg.closed.Load() {
+		return s.reg.nopDistribution
+	}
+
+	k := s.key(name, tags)
+
+	d, ok := loadSeries(s.reg, &s.reg.distributions, k.String())
+	if !ok {
+		d, _ = newSeries(s, &s.reg.distributions, k, name, tags, func(sr series) *Distribution {
+			s.reg.describe(sr.name, opts.meta)
+			dist := newDistribution(s.reg.dr, s.reg.splitDist, sr.name, sr.tags, s.reg.cfg.distBuckets)
+			dist.meta = opts.meta
+			dist.key = sr.key
+			dist.reg = s.reg
+			dist.overflow = sr.overflow
+			return dist
+		})
+	}
+
+	k.Release()
+
+	return d
+}
+
+// Distribution implements a distribution of values aggregated by the
+// backend, so percentiles can be computed across instances.
+//
+// When the reporter implements [DistributionReporter], values are forwarded
+// to it directly. Otherwise values are counted in fixed buckets, which are
+// reported each interval as _bucket counters tagged with the upper bound of
+// the bucket in [DistributionBucketTag], plus a _count counter. As in
+// Prometheus histograms, the bucket counts are cumulative and the last
+// bucket has an upper bound of +Inf.
+type Distribution struct {
+	activity
+
+	drFn     func(v float64)
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
+
+	bounds     []float64
+	bucketTags [][][2]string
+	counts     []atomic.Int64
+}
+
+// newDistribution returns a distribution delegating to dr when it handles
+// the distribution, counting buckets locally otherwise. If aggregate is set,
+// the distribution is counted locally even when delegated.
+func newDistribution(dr DistributionReporter, aggregate bool, name string, tags [][2]string, bounds []float64) *Distribution {
+	d := &Distribution{
+		name: name,
+		tags: tags,
+	}
+	if dr != nil {
+		d.drFn = dr.Distribution(name, tags)
+	}
+	if d.drFn == nil || aggregate {
+		d.bounds = bounds
+		d.bucketTags = bucketTags(tags, bounds)
+		d.counts = make([]atomic.Int64, len(bounds)+1)
+	}
+
+	return d
+}
+
+// Observe observes a value. Values delegated to the reporter are dropped
+// once the statter is closed.
+func (d *Distribution) Observe(v float64) {
+	d.touch()
+
+	if d.drFn != nil {
+		if d.reg.closed.Load() {
+			// The reporter may be closed.
+			return
+		}
+		d.drFn(v)
+		if d.counts == nil {
+			return
+		}
+	}
+
+	d.counts[sort.SearchFloat64s(d.bounds, v)].Add(1)
+}
+
+// Delete removes the distribution. Delete is a no-op once the statter is
+// closed.
+func (d *Distribution) Delete() {
+	if d.reg.closed.Load() {
+		return
+	}
+
+	if rdr, ok := d.reg.dr.(RemovableDistributionReporter); ok && d.drFn != nil {
+		rdr.RemoveDistribution(d.name, d.tags)
+	}
+	if rr, ok := d.reg.da.(RemovableReporter); ok && d.counts != nil {
+		for _, t := range d.bucketTags {
+			rr.RemoveCounter(d.name+"_bucket", t)
+		}
+		rr.RemoveCounter(d.name+"_count", d.tags)
+	}
+	if v, ok := d.reg.distributions.LoadAndDelete(d.key); ok {
+		v.deleted.Store(true)
+		d.reg.removed(d.name, d.overflow)
+	}
+}
+
+// report reports the bucket counts since the last report to r,
+// resetting them.
+func (d *Distribution) report(r Reporter) {
+	var total int64
+	for i := range d.counts {
+		total += d.counts[i].Swap(0)
+		if total == 0 {
+			continue
+		}
+		reportAggregateCounter(r, d.name, d.name+"_bucket", total, d.bucketTags[i])
+	}
+	if total == 0 {
+		return
+	}
+	reportAggregateCounter(r, d.name, d.name+"_count", total, d.tags)
+}
+
+// count returns the number of values observed since the last report.
+func (d *Distribution) count() int64 {
+	var total int64
+	for i := range d.counts {
+		total += d.counts[i].Load()
+	}
+	return total
+}
+
+func bucketTags(tags [][2]string, bounds []float64) [][][2]string {
+	bt := make([][][2]string, len(bounds)+1)
+	for i := range bt {
+		le := "+Inf"
+		if i < len(bounds) {
+			le = strconv.FormatFloat(bounds[i], 'g', -1, 64)
+		}
+
+		t := make([][2]string, len(tags), len(tags)+1)
+		copy(t, tags)
+		t = append(t, [2]string{DistributionBucketTag, le})
+		sortTags(t)
+		bt[i] = t
+	}
+	return bt
+}
+
+// validBuckets returns the sorted finite bounds of buckets.
+func validBuckets(buckets []float64) []float64 {
+	b := make([]float64, 0, len(buckets))
+	for _, v := range buckets {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		b = append(b, v)
+	}
+	sort.Float64s(b)
+	return b
+}
@@ -0,0 +1,163 @@
+package statter_test
+
+import (
+	"testing"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_Distribution(t *testing.T) {
+	m := &mockDistributionReporter{}
+	var got []float64
+	m.On("Distribution", "test", [][2]string{{"tag", "test"}}).Return(func(v float64) { got = append(got, v) })
+
+	stats := statter.New(m, 0)
+
+	d := stats.Distribution("test", tags.Str("tag", "test"))
+	d.Observe(1.5)
+	d.Observe(2.5)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{1.5, 2.5}, got)
+	m.AssertExpectations(t)
+}
+
+func TestStatter_DistributionDoesNotDelegateAfterClose(t *testing.T) {
+	m := &mockDistributionReporter{}
+	var got []float64
+	m.On("Distribution", "test", [][2]string{}).Return(func(v float64) { got = append(got, v) })
+
+	stats := statter.New(m, 0)
+
+	d := stats.Distribution("test")
+	d.Observe(1.5)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	d.Observe(2.5)
+
+	assert.Equal(t, []float64{1.5}, got)
+	m.AssertExpectations(t)
+}
+
+func TestStatter_DistributionAggregated(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test_bucket", int64(2), [][2]string{{"le", "1"}, {"tag", "test"}}).Once()
+	m.On("Counter", "test_bucket", int64(3), [][2]string{{"le", "5"}, {"tag", "test"}}).Once()
+	m.On("Counter", "test_bucket", int64(4), [][2]string{{"le", "+Inf"}, {"tag", "test"}}).Once()
+	m.On("Counter", "test_count", int64(4), [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0, statter.WithDistributionBuckets([]float64{5, 1}))
+
+	d := stats.Distribution("test", tags.Str("tag", "test"))
+	for _, v := range []float64{0.5, 1, 3, 10} {
+		d.Observe(v)
+	}
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_DistributionAggregatedSkipsEmptyBuckets(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test_bucket", int64(1), [][2]string{{"le", "5"}}).Once()
+	m.On("Counter", "test_bucket", int64(1), [][2]string{{"le", "+Inf"}}).Once()
+	m.On("Counter", "test_count", int64(1), [][2]string{}).Once()
+
+	stats := statter.New(m, 0, statter.WithDistributionBuckets([]float64{1, 5}))
+
+	stats.Distribution("test").Observe(3)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_DistributionReturnsIdenticalDistribution(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	d := stats.Distribution("test", tags.Str("tag", "test"))
+
+	assert.Same(t, d, stats.Distribution("test", tags.Str("tag", "test")))
+	assert.True(t, stats.HasDistribution("test", tags.Str("tag", "test")))
+}
+
+func TestStatter_DistributionDelete(t *testing.T) {
+	m := &mockDistributionReporter{}
+	m.On("Distribution", "test", [][2]string{}).Return(func(float64) {})
+	m.On("RemoveDistribution", "test", [][2]string{}).Once()
+
+	stats := statter.New(m, 0)
+
+	stats.Distribution("test").Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.False(t, stats.HasDistribution("test"))
+	m.AssertExpectations(t)
+}
+
+func TestStatter_DistributionAggregatedDelete(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.On("RemoveCounter", "test_bucket", [][2]string{{"le", "1"}}).Once()
+	m.On("RemoveCounter", "test_bucket", [][2]string{{"le", "+Inf"}}).Once()
+	m.On("RemoveCounter", "test_count", [][2]string{}).Once()
+
+	stats := statter.New(m, 0, statter.WithDistributionBuckets([]float64{1}))
+
+	stats.Distribution("test").Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_DistributionWithMultiReporter(t *testing.T) {
+	native := &mockDistributionReporter{}
+	native.On("Distribution", "test", [][2]string{}).Return(func(float64) {})
+	agg := &mockSimpleReporter{}
+	agg.On("Counter", "test_bucket", int64(1), [][2]string{{"le", "+Inf"}}).Once()
+	agg.On("Counter", "test_count", int64(1), [][2]string{}).Once()
+
+	stats := statter.New(statter.MultiReporter(native, agg), 0, statter.WithDistributionBuckets([]float64{1}))
+
+	stats.Distribution("test").Observe(2)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	native.AssertExpectations(t)
+	agg.AssertExpectations(t)
+}
+
+type mockDistributionReporter struct {
+	mockSimpleReporter
+}
+
+func (r *mockDistributionReporter) Distribution(name string, tags [][2]string) func(v float64) {
+	args := r.Called(name, tags)
+
+	ret := args.Get(0)
+	if ret == nil {
+		return nil
+	}
+	return ret.(func(v float64))
+}
+
+func (r *mockDistributionReporter) RemoveDistribution(name string, tags [][2]string) {
+	_ = r.Called(name, tags)
+}
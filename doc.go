@@ -1,18 +1,57 @@
 // Package statter collects and reports statistics via a pluggable [Reporter].
 //
 // Stats are aggregated in memory and flushed to the reporter on a fixed
-// interval. Counters accumulate deltas between flushes; gauges hold their
-// last-set value. Histograms and timings are either delegated directly to
-// the reporter (when it implements [HistogramReporter] / [TimingReporter])
-// or aggregated locally and emitted as a set of derived gauges and a counter.
+// interval, optionally aligned to wall-clock boundaries with
+// [WithAlignedFlush] and spread with [WithFlushJitter]. Reporting may be
+// suspended with [Statter.Pause], stats accumulating until [Statter.Resume].
+// Counters accumulate deltas between flushes; gauges hold their last-set
+// value. Histograms and timings are either delegated directly to the
+// reporter (when it implements [HistogramReporter] / [TimingReporter]) or
+// aggregated locally and emitted as a set of derived gauges and a counter.
+// Local aggregates are sharded, see [WithAggregationShards], so concurrent
+// observations rarely contend.
+// Sets count unique values, either natively by reporters implementing
+// [SetReporter] or estimated locally and emitted as a gauge. Distributions
+// are aggregated by the backend through reporters implementing
+// [DistributionReporter], or counted locally in mergeable fixed buckets.
+// Gauge functions, created with [Statter.GaugeFunc], are called on each
+// flush, or at scrape time by reporters implementing [GaugeFuncReporter].
+// Counter functions, created with [Statter.CounterFunc], return a cumulative
+// total from which the increase since the last flush is reported.
 //
 // Metrics are identified by a name and an optional set of key/value [Tag]
 // pairs. The [Statter.With] method creates a scoped sub-statter that
 // prepends a prefix and merges tags into every metric it records. Sub-statters
 // with identical resolved prefix and tags are deduplicated and share the same
-// instance.
+// instance. A [TagPolicy], set with [WithTagPolicy], restricts and normalizes
+// the tags of all metrics before they reach the reporter. Metric vectors, such
+// as [Statter.CounterVec], resolve metrics with fixed tag keys from their tag
+// values alone, for hot paths.
+//
+// Metric options, such as [Help] and [Unit], are set on the metrics created
+// through [Statter.Options]. They are not part of the metric tags, and are
+// passed to reporters implementing [DescribingReporter]. The [SampleRate]
+// option records only a fraction of the events of hot metrics, scaling the
+// reported stats back up, or passing the rate to reporters implementing
+// [SampledReporter].
+//
+// [Statter.Snapshot] returns a read-only view of the series currently held,
+// without resetting them, for use in debug endpoints, health checks and tests.
+//
+// Histogram and timing observations may carry exemplars, such as the trace ID
+// extracted from a context by the function set with [WithTraceIDExtractor].
+// Exemplars are passed to reporters implementing [ExemplarReporter], and
+// dropped otherwise.
 //
 // The [Reporter] interface is the only contract that backend adapters must
 // satisfy. Richer adapters may additionally implement [HistogramReporter],
-// [TimingReporter], and the corresponding Removable* interfaces.
+// [TimingReporter], [SetReporter], [DistributionReporter], the corresponding
+// Removable* interfaces, and [ErrorReporter] to surface send and registration
+// failures, [BatchReporter] to handle the stats of each flush as a batch,
+// or [AggregateReporter] to learn the metric that local aggregates derive
+// from. Use [MultiReporter] to report to several backends at once.
+//
+// Shared libraries may instrument themselves with the statter returned by
+// [Default], leaving applications to choose the backend at startup with
+// [SetDefault].
 package statter
@@ -0,0 +1,93 @@
+package statter
+
+import "errors"
+
+// ErrClosed is returned when flushing or closing a statter that has
+// already been closed.
+var ErrClosed = errors.New("statter is closed")
+
+// ErrCallbackTimeout is the error of a [CallbackError] raised when
+// a metric callback does not return within the callback timeout.
+var ErrCallbackTimeout = errors.New("callback timed out")
+
+// ErrTagValues is the error passed to the error handler when a metric
+// vector is given a number of tag values other than its number of tag keys.
+var ErrTagValues = errors.New("tag values do not match tag keys")
+
+// CloseStage is a stage of closing a statter.
+type CloseStage string
+
+// Close stages.
+const (
+	// CloseStageFlush is the final flush of pending stats,
+	// including waiting for the reporting loop to stop.
+	CloseStageFlush CloseStage = "flush"
+	// CloseStageReporter is the closing of the reporter.
+	CloseStageReporter CloseStage = "reporter"
+)
+
+// CloseError is returned when a close stage does not complete
+// before the close context is done.
+type CloseError struct {
+	Stage CloseStage
+	Err   error
+}
+
+// Error returns the error message.
+func (e *CloseError) Error() string {
+	return "statter: close " + string(e.Stage) + " did not complete: " + e.Err.Error()
+}
+
+// Unwrap returns the underlying context error.
+func (e *CloseError) Unwrap() error {
+	return e.Err
+}
+
+// ReporterError is an error encountered by a reporter while
+// registering or sending a metric.
+type ReporterError struct {
+	Reporter Reporter
+	Name     string
+	Tags     [][2]string
+	Err      error
+}
+
+// Error returns the error message.
+func (e *ReporterError) Error() string {
+	return "statter: reporter error for " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying reporter error.
+func (e *ReporterError) Unwrap() error {
+	return e.Err
+}
+
+// SeriesLimitError is passed to the error handler when a series limit is
+// first reached for a metric name and an overflow series is created.
+type SeriesLimitError struct {
+	Name string
+	Tags [][2]string
+}
+
+// Error returns the error message.
+func (e *SeriesLimitError) Error() string {
+	return "statter: series limit reached for " + e.Name + ", redirecting new series to overflow series"
+}
+
+// CallbackError is passed to the error handler when a metric callback
+// panics or does not return within the callback timeout.
+type CallbackError struct {
+	Name string
+	Tags [][2]string
+	Err  error
+}
+
+// Error returns the error message.
+func (e *CallbackError) Error() string {
+	return "statter: callback error for " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying callback error.
+func (e *CallbackError) Unwrap() error {
+	return e.Err
+}
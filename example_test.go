@@ -24,3 +24,27 @@ func ExampleHistogram_Observe() {
 
 	stat.Histogram("my_histo", tags.Str("label", "blah")).Observe(2.34)
 }
+
+func ExampleCounterVec_With() {
+	stat := statter.New(statter.DiscardReporter, time.Second)
+
+	requests := stat.CounterVec("requests", "method", "code")
+
+	requests.With("GET", "200").Inc(1)
+}
+
+func ExampleStatter_Options() {
+	stat := statter.New(statter.DiscardReporter, time.Second)
+
+	stat.Options(statter.Help("Requests served."), statter.Unit("requests")).
+		Counter("requests", tags.Str("method", "GET")).
+		Inc(1)
+}
+
+func ExampleSetDefault() {
+	stat := statter.New(statter.DiscardReporter, time.Second)
+	statter.SetDefault(stat)
+
+	// Shared libraries use the default statter.
+	statter.Default().Counter("my_counter").Inc(1)
+}
@@ -0,0 +1,99 @@
+package statter
+
+import (
+	"context"
+	"time"
+)
+
+// TraceIDLabel is the exemplar label holding the trace ID extracted
+// by the function set with [WithTraceIDExtractor].
+const TraceIDLabel = "trace_id"
+
+// ExemplarReporter represents a stats reporter that handles exemplars,
+// such as trace IDs, attached to histogram and timing observations.
+type ExemplarReporter interface {
+	HistogramWithExemplar(name string, tags [][2]string) func(v float64, exemplar [][2]string)
+	TimingWithExemplar(name string, tags [][2]string) func(v time.Duration, exemplar [][2]string)
+}
+
+// WithTraceIDExtractor sets the function extracting the trace ID from a
+// context, used by ObserveContext to attach the trace ID as an exemplar
+// under [TraceIDLabel]. An empty trace ID attaches no exemplar.
+//
+// This keeps the statter free of any tracing dependency; with OpenTelemetry,
+// the function would typically return the trace ID of
+// trace.SpanContextFromContext(ctx) when it is valid.
+func WithTraceIDExtractor(fn func(ctx context.Context) string) Option {
+	return func(c *config) {
+		c.traceIDFn = fn
+	}
+}
+
+// exemplar returns the trace ID exemplar of ctx, or nil if there is none.
+func (r *registry) exemplar(ctx context.Context) []Tag {
+	if r.cfg.traceIDFn == nil {
+		return nil
+	}
+
+	id := r.cfg.traceIDFn(ctx)
+	if id == "" {
+		return nil
+	}
+	return []Tag{{TraceIDLabel, id}}
+}
+
+// ObserveWithExemplar observes a histogram value, attaching the exemplar
+// labels to it when the reporter implements [ExemplarReporter].
+func (h *Histogram) ObserveWithExemplar(v float64, exemplar ...Tag) {
+	if h.exFn == nil || len(exemplar) == 0 {
+		h.Observe(v)
+		return
+	}
+
+	h.touch()
+
+	if h.reg.closed.Load() {
+		// The reporter may be closed.
+		return
+	}
+	h.exFn(v, exemplar)
+	if h.s == nil {
+		return
+	}
+
+	h.s.Add(v)
+}
+
+// ObserveContext observes a histogram value, attaching the trace ID
+// of ctx as an exemplar, see [WithTraceIDExtractor].
+func (h *Histogram) ObserveContext(ctx context.Context, v float64) {
+	h.ObserveWithExemplar(v, h.reg.exemplar(ctx)...)
+}
+
+// ObserveWithExemplar observes a timing duration, attaching the exemplar
+// labels to it when the reporter implements [ExemplarReporter].
+func (t *Timing) ObserveWithExemplar(d time.Duration, exemplar ...Tag) {
+	if t.exFn == nil || len(exemplar) == 0 {
+		t.Observe(d)
+		return
+	}
+
+	t.touch()
+
+	if t.reg.closed.Load() {
+		// The reporter may be closed.
+		return
+	}
+	t.exFn(d, exemplar)
+	if t.s == nil {
+		return
+	}
+
+	t.s.Add(d.Seconds() * 1000)
+}
+
+// ObserveContext observes a timing duration, attaching the trace ID
+// of ctx as an exemplar, see [WithTraceIDExtractor].
+func (t *Timing) ObserveContext(ctx context.Context, d time.Duration) {
+	t.ObserveWithExemplar(d, t.reg.exemplar(ctx)...)
+}
@@ -0,0 +1,174 @@
+package statter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type traceIDKey struct{}
+
+func traceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+func TestHistogram_ObserveWithExemplar(t *testing.T) {
+	m := &mockExemplarReporter{}
+	m.On("Histogram", "test", [][2]string{{"tag", "test"}}).Return(func(float64) {})
+	var got [][2]string
+	m.On("HistogramWithExemplar", "test", [][2]string{{"tag", "test"}}).Return(func(v float64, exemplar [][2]string) {
+		assert.Equal(t, 1.23, v)
+		got = exemplar
+	})
+
+	stats := statter.New(m, 0)
+
+	stats.Histogram("test", tags.Str("tag", "test")).ObserveWithExemplar(1.23, tags.Str("trace_id", "abc"))
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][2]string{{"trace_id", "abc"}}, got)
+	m.AssertExpectations(t)
+}
+
+func TestHistogram_ObserveContext(t *testing.T) {
+	m := &mockExemplarReporter{}
+	m.On("Histogram", "test", [][2]string{}).Return(func(float64) {})
+	var got [][2]string
+	m.On("HistogramWithExemplar", "test", [][2]string{}).Return(func(_ float64, exemplar [][2]string) {
+		got = exemplar
+	})
+
+	stats := statter.New(m, 0, statter.WithTraceIDExtractor(traceID))
+
+	ctx := context.WithValue(t.Context(), traceIDKey{}, "abc")
+	stats.Histogram("test").ObserveContext(ctx, 1.23)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][2]string{{statter.TraceIDLabel, "abc"}}, got)
+	m.AssertExpectations(t)
+}
+
+func TestHistogram_ObserveContextWithoutTraceID(t *testing.T) {
+	m := &mockExemplarReporter{}
+	var called bool
+	m.On("Histogram", "test", [][2]string{}).Return(func(float64) { called = true })
+	m.On("HistogramWithExemplar", "test", [][2]string{}).Return(func(float64, [][2]string) {
+		assert.Fail(t, "unexpected exemplar")
+	})
+
+	stats := statter.New(m, 0, statter.WithTraceIDExtractor(traceID))
+
+	stats.Histogram("test").ObserveContext(t.Context(), 1.23)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.True(t, called)
+}
+
+func TestHistogram_ObserveWithExemplarFallsBack(t *testing.T) {
+	m := &mockComplexReporter{}
+	var got float64
+	m.On("Histogram", "test", [][2]string{}).Return(func(v float64) { got = v })
+
+	stats := statter.New(m, 0)
+
+	stats.Histogram("test").ObserveWithExemplar(1.23, tags.Str("trace_id", "abc"))
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.23, got)
+}
+
+func TestTiming_ObserveContext(t *testing.T) {
+	m := &mockExemplarReporter{}
+	m.On("Timing", "test", [][2]string{}).Return(func(time.Duration) {})
+	var got [][2]string
+	m.On("TimingWithExemplar", "test", [][2]string{}).Return(func(v time.Duration, exemplar [][2]string) {
+		assert.Equal(t, time.Second, v)
+		got = exemplar
+	})
+
+	stats := statter.New(m, 0, statter.WithTraceIDExtractor(traceID))
+
+	ctx := context.WithValue(t.Context(), traceIDKey{}, "abc")
+	stats.Timing("test").ObserveContext(ctx, time.Second)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][2]string{{statter.TraceIDLabel, "abc"}}, got)
+	m.AssertExpectations(t)
+}
+
+func TestTiming_ObserveWithExemplarFallsBack(t *testing.T) {
+	m := &mockComplexReporter{}
+	var got time.Duration
+	m.On("Timing", "test", [][2]string{}).Return(func(v time.Duration) { got = v })
+
+	stats := statter.New(m, 0)
+
+	stats.Timing("test").ObserveWithExemplar(time.Second, tags.Str("trace_id", "abc"))
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Second, got)
+}
+
+func TestHistogram_ObserveWithExemplarWithMultiReporter(t *testing.T) {
+	ex := &mockExemplarReporter{}
+	ex.On("Histogram", "test", [][2]string{}).Return(func(float64) {})
+	var gotEx [][2]string
+	ex.On("HistogramWithExemplar", "test", [][2]string{}).Return(func(_ float64, exemplar [][2]string) {
+		gotEx = exemplar
+	})
+	plain := &mockComplexReporter{}
+	var got float64
+	plain.On("Histogram", "test", [][2]string{}).Return(func(v float64) { got = v })
+
+	stats := statter.New(statter.MultiReporter(ex, plain), 0)
+
+	stats.Histogram("test").ObserveWithExemplar(1.23, tags.Str("trace_id", "abc"))
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, [][2]string{{"trace_id", "abc"}}, gotEx)
+	assert.Equal(t, 1.23, got)
+}
+
+type mockExemplarReporter struct {
+	mockComplexReporter
+}
+
+func (r *mockExemplarReporter) HistogramWithExemplar(name string, tags [][2]string) func(v float64, exemplar [][2]string) {
+	args := r.Called(name, tags)
+
+	ret := args.Get(0)
+	if ret == nil {
+		return nil
+	}
+	return ret.(func(v float64, exemplar [][2]string))
+}
+
+func (r *mockExemplarReporter) TimingWithExemplar(name string, tags [][2]string) func(v time.Duration, exemplar [][2]string) {
+	args := r.Called(name, tags)
+
+	ret := args.Get(0)
+	if ret == nil {
+		return nil
+	}
+	return ret.(func(v time.Duration, exemplar [][2]string))
+}
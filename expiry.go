@@ -0,0 +1,90 @@
+package statter
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// activity tracks the updates of a series for series expiry.
+type activity struct {
+	updated  atomic.Bool
+	seen     atomic.Bool
+	lastSeen atomic.Int64
+	// deleted is set once the series is removed from the statter,
+	// by deletion or expiry.
+	deleted atomic.Bool
+}
+
+// touch marks the series as updated. The flag is only written when
+// unset to avoid contending on the cache line in hot paths.
+func (a *activity) touch() {
+	if !a.updated.Load() {
+		a.updated.Store(true)
+	}
+}
+
+// isDeleted determines if the series has been removed from the statter.
+func (a *activity) isDeleted() bool {
+	return a.deleted.Load()
+}
+
+// expired determines if the series has not been updated for longer than
+// ttl, given the current time in nanoseconds. A series is seen at the
+// first check after its creation.
+func (a *activity) expired(now int64, ttl time.Duration) bool {
+	if a.updated.Swap(false) || !a.seen.Load() {
+		a.lastSeen.Store(now)
+		a.seen.Store(true)
+		return false
+	}
+	return now-a.lastSeen.Load() > int64(ttl)
+}
+
+// expire deletes all series that have not been updated within the
+// configured series TTL.
+func (r *registry) expire(now time.Time) {
+	ttl := r.cfg.seriesTTL
+	ts := now.UnixNano()
+
+	r.counters.Range(func(_ string, c *Counter) bool {
+		if c.expired(ts, ttl) {
+			c.Delete()
+		}
+		return true
+	})
+
+	r.gauges.Range(func(_ string, g *Gauge) bool {
+		if g.expired(ts, ttl) {
+			g.Delete()
+		}
+		return true
+	})
+
+	r.histograms.Range(func(_ string, h *Histogram) bool {
+		if h.expired(ts, ttl) {
+			h.Delete()
+		}
+		return true
+	})
+
+	r.timings.Range(func(_ string, t *Timing) bool {
+		if t.expired(ts, ttl) {
+			t.Delete()
+		}
+		return true
+	})
+
+	r.sets.Range(func(_ string, s *Set) bool {
+		if s.expired(ts, ttl) {
+			s.Delete()
+		}
+		return true
+	})
+
+	r.distributions.Range(func(_ string, d *Distribution) bool {
+		if d.expired(ts, ttl) {
+			d.Delete()
+		}
+		return true
+	})
+}
@@ -0,0 +1,114 @@
+package statter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_WithSeriesTTLExpiresStaleSeries(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.On("Gauge", "test", 1.23, [][2]string{{"tag", "test"}})
+	m.On("RemoveGauge", "test", [][2]string{{"tag", "test"}}).Once()
+	m.On("Counter", "test", int64(1), [][2]string{{"tag", "test"}}).Once()
+	m.On("RemoveCounter", "test", [][2]string{{"tag", "test"}}).Once()
+	m.On("Histogram", "test", [][2]string{{"tag", "test"}}).Return(func(float64) {})
+	m.On("RemoveHistogram", "test", [][2]string{{"tag", "test"}}).Once()
+	m.On("Timing", "test", [][2]string{{"tag", "test"}}).Return(func(time.Duration) {})
+	m.On("RemoveTiming", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0, statter.WithSeriesTTL(time.Millisecond))
+
+	stats.Gauge("test", tags.Str("tag", "test")).Set(1.23)
+	stats.Counter("test", tags.Str("tag", "test")).Inc(1)
+	stats.Histogram("test", tags.Str("tag", "test")).Observe(1)
+	stats.Timing("test", tags.Str("tag", "test")).Observe(time.Second)
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	assert.False(t, stats.HasGauge("test", tags.Str("tag", "test")))
+	assert.False(t, stats.HasCounter("test", tags.Str("tag", "test")))
+	assert.False(t, stats.HasHistogram("test", tags.Str("tag", "test")))
+	assert.False(t, stats.HasTiming("test", tags.Str("tag", "test")))
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_WithSeriesTTLKeepsUpdatedSeries(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.On("Gauge", "test", 1.23, [][2]string{{"tag", "test"}})
+
+	stats := statter.New(m, 0, statter.WithSeriesTTL(time.Millisecond))
+
+	g := stats.Gauge("test", tags.Str("tag", "test"))
+	g.Set(1.23)
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	g.Set(1.23)
+
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	assert.True(t, stats.HasGauge("test", tags.Str("tag", "test")))
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertNotCalled(t, "RemoveGauge", mock.Anything, mock.Anything)
+}
+
+func TestStatter_WithSeriesTTLExpiresAtEpoch(t *testing.T) {
+	clock := statstest.NewClock(time.Unix(0, 0))
+	stats, _ := statstest.NewStatter(t, statter.WithClock(clock), statter.WithSeriesTTL(time.Minute))
+
+	stats.Gauge("test").Set(1)
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	clock.Advance(2 * time.Minute)
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	assert.False(t, stats.HasGauge("test"))
+}
+
+func TestStatter_WithSeriesTTLDropsUpdatesOfExpiredSeries(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	stats, r := statstest.NewStatter(t, statter.WithClock(clock), statter.WithSeriesTTL(time.Minute))
+
+	c := stats.Counter("test")
+	c.Inc(1)
+	r.Flush(t)
+
+	clock.Advance(2 * time.Minute)
+	r.Flush(t)
+
+	require.False(t, stats.HasCounter("test"))
+
+	// A held counter is not reported once expired.
+	c.Inc(5)
+	r.AssertNoCounter(t, "test", nil)
+
+	// Fetching the counter again re-creates the series.
+	stats.Counter("test").Inc(2)
+	r.AssertCounter(t, "test", nil, 2)
+}
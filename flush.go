@@ -0,0 +1,92 @@
+package statter
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// FlushReporter represents a stats reporter that handles flush timestamps.
+//
+// BeginFlush is called with the timestamp of each flush before its stats
+// are reported. When flushes are aligned, see [WithAlignedFlush], the
+// timestamp is the interval boundary of the flush, regardless of jitter.
+// Timestamps never go backwards: a scheduled flush following a flush
+// triggered by Flush with a later timestamp has the same timestamp.
+//
+// The interval is the time covered by the stats of the flush, from the
+// timestamp of the previous flush, or the creation of the statter, to ts.
+// It is usually the report interval, but differs for the first aligned
+// flush, flushes resumed after a pause, and flushes triggered by Flush or
+// Close, including all flushes in manual mode.
+type FlushReporter interface {
+	BeginFlush(ts time.Time, interval time.Duration)
+}
+
+// BatchReporter represents a stats reporter that handles the stats of each
+// flush as a batch, such as a push-based reporter sending one request per
+// flush.
+//
+// The counters and gauges of a flush, including locally aggregated stats,
+// are reported between BeginFlush and EndFlush. Flushes never overlap.
+// Delegated observations, such as those of histograms handled by a
+// [HistogramReporter], and removals may be reported outside of a flush.
+type BatchReporter interface {
+	FlushReporter
+
+	EndFlush()
+}
+
+// WithAlignedFlush aligns flushes to the wall-clock boundaries of the
+// interval, so that with a 10s interval stats are flushed at :00, :10,
+// :20 and so on, rather than relative to when the statter was created.
+// Boundaries are computed in UTC.
+func WithAlignedFlush() Option {
+	return func(c *config) {
+		c.alignFlush = true
+	}
+}
+
+// WithFlushJitter delays each flush by a random duration of up to max,
+// spreading the load of many statters flushing at the same interval.
+// Jitter does not accumulate; the schedule of flushes is kept. A max
+// of zero or less disables jitter.
+func WithFlushJitter(maxJitter time.Duration) Option {
+	return func(c *config) {
+		c.flushJitter = maxJitter
+	}
+}
+
+// later returns the later of a and b.
+func later(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return b
+	}
+	return a
+}
+
+// firstFlush returns the scheduled time of the first flush.
+func (r *registry) firstFlush(now time.Time, d time.Duration) time.Time {
+	if r.cfg.alignFlush {
+		return now.Truncate(d).Add(d)
+	}
+	return now.Add(d)
+}
+
+// nextFlush returns the scheduled time of the flush following the flush
+// scheduled at last, skipping the flushes missed by now.
+func nextFlush(last, now time.Time, d time.Duration) time.Time {
+	next := last.Add(d)
+	if next.After(now) {
+		return next
+	}
+	// Skip missed flushes, keeping the schedule.
+	return next.Add(now.Sub(next).Truncate(d) + d)
+}
+
+// jitter returns a random flush delay.
+func (r *registry) jitter() time.Duration {
+	if r.cfg.flushJitter <= 0 {
+		return 0
+	}
+	return rand.N(r.cfg.flushJitter)
+}
@@ -0,0 +1,237 @@
+package statter_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_FlushPassesTimestamp(t *testing.T) {
+	r := newFlushReporter()
+
+	stats := statter.New(r, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	before := time.Now()
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	f := r.next(t)
+	assert.False(t, f.ts.Before(before))
+	assert.False(t, f.ts.After(time.Now()))
+}
+
+func TestStatter_FlushPassesIntervalInManualMode(t *testing.T) {
+	r := newFlushReporter()
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	stats := statter.New(r, 0, statter.WithClock(clock))
+	t.Cleanup(func() { _ = stats.Close() })
+
+	clock.Advance(5 * time.Second)
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	clock.Advance(2 * time.Second)
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	first, second := r.next(t), r.next(t)
+	assert.Equal(t, clock.Now().Add(-2*time.Second), first.ts)
+	assert.Equal(t, 5*time.Second, first.interval)
+	assert.Equal(t, clock.Now(), second.ts)
+	assert.Equal(t, 2*time.Second, second.interval)
+}
+
+func TestStatter_AlignedFlush(t *testing.T) {
+	r := newFlushReporter()
+
+	stats := statter.New(r, 20*time.Millisecond, statter.WithAlignedFlush())
+	t.Cleanup(func() { _ = stats.Close() })
+
+	first := r.next(t)
+	second := r.next(t)
+
+	assert.Equal(t, first.ts, first.ts.Truncate(20*time.Millisecond))
+	assert.Equal(t, second.ts, second.ts.Truncate(20*time.Millisecond))
+	assert.Equal(t, second.ts.Sub(first.ts), second.interval)
+	assert.True(t, second.ts.After(first.ts))
+}
+
+func TestStatter_FlushJitter(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 3, 0, time.UTC))
+	r := newFlushReporter()
+
+	stats := statter.New(r, 20*time.Second, statter.WithClock(clock), statter.WithAlignedFlush(), statter.WithFlushJitter(10*time.Second))
+	t.Cleanup(func() { _ = stats.Close() })
+
+	clock.BlockUntilTimers(1)
+	clock.Advance(17 * time.Second)
+	r.none(t)
+
+	clock.Advance(10 * time.Second)
+
+	assert.Equal(t, flush{ts: time.Date(2024, 1, 1, 0, 0, 20, 0, time.UTC), interval: 17 * time.Second}, r.next(t))
+}
+
+func TestStatter_FlushTimestampsNeverGoBackwards(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := newFlushReporter()
+
+	stats := statter.New(r, 10*time.Second, statter.WithClock(clock), statter.WithFlushJitter(5*time.Second))
+	t.Cleanup(func() { _ = stats.Close() })
+
+	clock.BlockUntilTimers(1)
+	clock.Advance(10*time.Second + time.Nanosecond)
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	clock.Advance(5 * time.Second)
+
+	manual, scheduled := r.next(t), r.next(t)
+	assert.Equal(t, clock.Now().Add(-5*time.Second), manual.ts)
+	assert.Equal(t, manual.ts, scheduled.ts)
+	assert.Zero(t, scheduled.interval)
+}
+
+func TestMultiReporter_BeginFlush(t *testing.T) {
+	r1, r2 := newFlushReporter(), newFlushReporter()
+
+	stats := statter.New(statter.MultiReporter(r1, &mockSimpleReporter{}, r2), 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, r1.next(t), r2.next(t))
+}
+
+func TestStatter_BatchReporter(t *testing.T) {
+	r := &batchReporter{}
+
+	stats := statter.New(r, 0)
+
+	stats.Counter("test", tags.Str("tag", "test")).Inc(2)
+	stats.Gauge("test").Set(1.5)
+	stats.Histogram("histo").Observe(1)
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+	err = stats.Close()
+	require.NoError(t, err)
+
+	require.Len(t, r.batches, 2)
+	assert.False(t, r.batches[0].ts.IsZero())
+	assert.Contains(t, r.batches[0].names, "test")
+	assert.Contains(t, r.batches[0].names, "histo_count")
+	assert.False(t, r.open)
+}
+
+func TestMultiReporter_EndFlush(t *testing.T) {
+	r1, r2 := &batchReporter{}, &batchReporter{}
+
+	stats := statter.New(statter.MultiReporter(r1, &mockSimpleReporter{}, r2), 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	assert.Len(t, r1.batches, 1)
+	assert.Len(t, r2.batches, 1)
+}
+
+type flush struct {
+	ts       time.Time
+	interval time.Duration
+}
+
+type flushReporter struct {
+	ch chan flush
+}
+
+func newFlushReporter() *flushReporter {
+	return &flushReporter{ch: make(chan flush, 10)}
+}
+
+func (r *flushReporter) BeginFlush(ts time.Time, interval time.Duration) {
+	select {
+	case r.ch <- flush{ts: ts, interval: interval}:
+	default:
+	}
+}
+
+func (r *flushReporter) Counter(string, int64, [][2]string) {}
+
+func (r *flushReporter) Gauge(string, float64, [][2]string) {}
+
+func (r *flushReporter) next(t *testing.T) flush {
+	t.Helper()
+
+	select {
+	case f := <-r.ch:
+		return f
+	case <-time.After(time.Second):
+		require.FailNow(t, "expected flush timed out")
+		return flush{}
+	}
+}
+
+func (r *flushReporter) none(t *testing.T) {
+	t.Helper()
+
+	select {
+	case f := <-r.ch:
+		assert.Fail(t, "unexpected flush", "flushed at %s", f.ts)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+type batch struct {
+	ts    time.Time
+	names []string
+}
+
+type batchReporter struct {
+	mu      sync.Mutex
+	open    bool
+	cur     batch
+	batches []batch
+}
+
+func (r *batchReporter) BeginFlush(ts time.Time, _ time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.open = true
+	r.cur = batch{ts: ts}
+}
+
+func (r *batchReporter) EndFlush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.open = false
+	r.batches = append(r.batches, r.cur)
+}
+
+func (r *batchReporter) Counter(name string, _ int64, _ [][2]string) {
+	r.add(name)
+}
+
+func (r *batchReporter) Gauge(name string, _ float64, _ [][2]string) {
+	r.add(name)
+}
+
+func (r *batchReporter) add(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.open {
+		panic("stat reported outside of a flush")
+	}
+	r.cur.names = append(r.cur.names, name)
+}
@@ -0,0 +1,58 @@
+package stats_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hamba/statter/v2/internal/stats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperLogLog(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+	}{
+		{name: "empty", n: 0},
+		{name: "small", n: 10},
+		{name: "medium", n: 1000},
+		{name: "large", n: 100000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := stats.NewHyperLogLog()
+
+			for i := range test.n {
+				v := strconv.Itoa(i)
+				h.Add(v)
+				h.Add(v)
+			}
+
+			assert.InEpsilon(t, float64(test.n)+1, float64(h.Count())+1, 0.065)
+		})
+	}
+}
+
+func TestHyperLogLog_Reset(t *testing.T) {
+	h := stats.NewHyperLogLog()
+	h.Add("test")
+
+	h.Reset()
+
+	assert.Zero(t, h.Count())
+}
+
+func BenchmarkHyperLogLog_Add(b *testing.B) {
+	h := stats.NewHyperLogLog()
+	vals := make([]string, 1024)
+	for i := range vals {
+		vals[i] = strconv.Itoa(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		h.Add(vals[i%len(vals)])
+	}
+}
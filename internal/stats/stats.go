@@ -92,6 +92,60 @@ func (s *Sample) Add(v float64) {
 	}
 }
 
+// Merge adds the values of o to the sample.
+//
+// When the percentile samples of both do not fit in the sample, each
+// contributes a random selection in proportion to its count. The order
+// of the percentile samples of o may change.
+func (s *Sample) Merge(o *Sample) {
+	if o.n == 0 {
+		return
+	}
+
+	if s.n == 0 {
+		s.k = o.k
+		s.max = o.max
+		s.min = o.min
+	}
+
+	// Shift the sums of o to the reference value of the sample.
+	d := o.k - s.k
+	s.ex2 += o.ex2 + 2*d*o.ex + float64(o.n)*d*d
+	s.ex += o.ex + float64(o.n)*d
+
+	s.sum += o.sum
+	s.max = max(s.max, o.max)
+	s.min = min(s.min, o.min)
+
+	s.mergePercentiles(o)
+	s.n += o.n
+}
+
+func (s *Sample) mergePercentiles(o *Sample) {
+	l, ol, c := len(s.perc), len(o.perc), cap(s.perc)
+	if l+ol <= c {
+		s.perc = append(s.perc, o.perc...)
+		return
+	}
+
+	keep := int(math.Round(float64(c) * float64(s.n) / float64(s.n+o.n)))
+	keep = min(keep, l)
+	add := min(c-keep, ol)
+	keep = c - add
+
+	shuffle(s.perc, keep)
+	shuffle(o.perc, add)
+	s.perc = append(s.perc[:keep], o.perc[:add]...)
+}
+
+// shuffle moves a random selection of n values to the front of vs.
+func shuffle(vs []float64, n int) {
+	for i := range n {
+		j := i + int(rand.Uint32N(uint32(len(vs)-i)))
+		vs[i], vs[j] = vs[j], vs[i]
+	}
+}
+
 // Reset resets the sample.
 func (s *Sample) Reset() {
 	s.n = 0
@@ -79,6 +79,66 @@ func TestSample_Underflow(t *testing.T) {
 	assert.Equal(t, ps, s.Percentiles([]float64{-1, 0, 50, 90, 99.5, 100}))
 }
 
+func TestSample_Merge(t *testing.T) {
+	s := stats.NewSample(1000)
+	o := stats.NewSample(1000)
+	for i := range 1000 * 10 {
+		if i%3 == 0 {
+			o.Add(float64(i))
+			continue
+		}
+		s.Add(float64(i))
+	}
+
+	s.Merge(o)
+
+	assert.Equal(t, int64(10000), s.Count())
+	assert.Equal(t, float64(49995000), s.Sum())
+	assert.InDelta(t, 4999.5, s.Mean(), 1e-9)
+	assert.Equal(t, float64(9999), s.Max())
+	assert.Equal(t, float64(0), s.Min())
+	assert.InDelta(t, 8333333.25, s.Variance(), 1e-3)
+	ps := s.Percentiles([]float64{50, 90})
+	assert.InDelta(t, 5000, ps[0], 500)
+	assert.InDelta(t, 9000, ps[1], 500)
+}
+
+func TestSample_MergeUnderflow(t *testing.T) {
+	s := stats.NewSample(1000)
+	o := stats.NewSample(1000)
+	for _, v := range []float64{10, 20, 10, 30, 20, 11, 12, 32} {
+		s.Add(v)
+	}
+	for _, v := range []float64{45, 9, 5, 5, 5, 10, 23, 8} {
+		o.Add(v)
+	}
+
+	s.Merge(o)
+
+	assert.Equal(t, int64(16), s.Count())
+	assert.Equal(t, float64(255), s.Sum())
+	assert.Equal(t, 15.9375, s.Mean())
+	assert.Equal(t, float64(45), s.Max())
+	assert.Equal(t, float64(5), s.Min())
+	ps := []float64{5, 5, 11, 32, 45, 45}
+	assert.Equal(t, ps, s.Percentiles([]float64{-1, 0, 50, 90, 99.5, 100}))
+}
+
+func TestSample_MergeEmpty(t *testing.T) {
+	s := stats.NewSample(1000)
+	o := stats.NewSample(1000)
+	o.Add(12.34)
+
+	s.Merge(stats.NewSample(1000))
+	s.Merge(o)
+
+	assert.Equal(t, int64(1), s.Count())
+	assert.Equal(t, 12.34, s.Mean())
+	assert.Equal(t, 12.34, s.Max())
+	assert.Equal(t, 12.34, s.Min())
+	assert.Zero(t, s.Variance())
+}
+
 func BenchmarkSample(b *testing.B) {
 	s := stats.NewSample(1000)
 
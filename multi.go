@@ -0,0 +1,479 @@
+package statter
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// MultiReporter returns a reporter that reports stats to all the given
+// reporters.
+//
+// Counters and gauges are reported to every reporter. Histograms and timings
+// are delegated to the reporters implementing [HistogramReporter] and
+// [TimingReporter]; for the remaining reporters they are aggregated locally
+// and reported as a set of gauges plus a _count counter, as they would be
+// when used on their own. Sets and distributions are likewise delegated to
+// the reporters implementing [SetReporter] and [DistributionReporter], and
+// aggregated locally for the others. Gauge functions are likewise registered
+// with the reporters implementing [GaugeFuncReporter] and reported as gauges
+// to the remaining reporters. Removals, errors and [io.Closer] are fanned
+// out to the reporters that support them, with close errors combined.
+//
+// Nested multi reporters are flattened, and reporters wrapping another
+// reporter, see [WrappingReporter], are split by the support of the
+// reporter they wrap. The reporters of a multi reporter are returned by
+// its Unwrap() []Reporter method, so that a wrapping reporter may wrap
+// each of them rather than the multi reporter as a whole.
+func MultiReporter(rs ...Reporter) Reporter {
+	m := &multiReporter{rs: make([]Reporter, 0, len(rs))}
+	for _, r := range rs {
+		if mr, ok := r.(*multiReporter); ok {
+			m.rs = append(m.rs, mr.rs...)
+			continue
+		}
+		m.rs = append(m.rs, r)
+	}
+	return m
+}
+
+// WrappingReporter represents a stats reporter wrapping another reporter,
+// such as a reporter middleware.
+//
+// The optional interfaces of a wrapping reporter that decide how metrics
+// are handled, such as [HistogramReporter], are only considered supported
+// when the wrapped reporter supports them too.
+type WrappingReporter interface {
+	Reporter
+
+	Unwrap() Reporter
+}
+
+type multiReporter struct {
+	rs []Reporter
+}
+
+// Unwrap returns the reporters of the multi reporter.
+func (m *multiReporter) Unwrap() []Reporter {
+	return m.rs
+}
+
+// split partitions the reporters into those that match fn and those that
+// do not, returning nil for an empty partition.
+func (m *multiReporter) split(fn func(Reporter) bool) (in, out *multiReporter) {
+	var ins, outs []Reporter
+	for _, r := range m.rs {
+		if fn(r) {
+			ins = append(ins, r)
+			continue
+		}
+		outs = append(outs, r)
+	}
+
+	if len(ins) > 0 {
+		in = &multiReporter{rs: ins}
+	}
+	if len(outs) > 0 {
+		out = &multiReporter{rs: outs}
+	}
+	return in, out
+}
+
+// OnError sets the error function on all reporters implementing
+// [ErrorReporter]. Errors are wrapped in a [*ReporterError] carrying
+// the reporter that raised them.
+func (m *multiReporter) OnError(fn func(name string, tags [][2]string, err error)) {
+	for _, r := range m.rs {
+		er, ok := r.(ErrorReporter)
+		if !ok {
+			continue
+		}
+
+		er.OnError(func(name string, tags [][2]string, err error) {
+			fn(name, tags, &ReporterError{Reporter: r, Name: name, Tags: tags, Err: err})
+		})
+	}
+}
+
+// Describe passes the metric metadata to all reporters implementing
+// [DescribingReporter].
+func (m *multiReporter) Describe(name string, meta Metadata) {
+	for _, r := range m.rs {
+		if dr, ok := r.(DescribingReporter); ok {
+			dr.Describe(name, meta)
+		}
+	}
+}
+
+// AggregateCounter reports a counter aggregated from metric to all
+// reporters, see [AggregateReporter].
+func (m *multiReporter) AggregateCounter(metric, name string, v int64, tags [][2]string) {
+	for _, r := range m.rs {
+		reportAggregateCounter(r, metric, name, v, tags)
+	}
+}
+
+// AggregateGauge reports a gauge aggregated from metric to all reporters,
+// see [AggregateReporter].
+func (m *multiReporter) AggregateGauge(metric, name string, v float64, tags [][2]string) {
+	for _, r := range m.rs {
+		reportAggregateGauge(r, metric, name, v, tags)
+	}
+}
+
+// BeginFlush passes the flush timestamp and interval to all reporters
+// implementing [FlushReporter].
+func (m *multiReporter) BeginFlush(ts time.Time, interval time.Duration) {
+	for _, r := range m.rs {
+		if fr, ok := r.(FlushReporter); ok {
+			fr.BeginFlush(ts, interval)
+		}
+	}
+}
+
+// EndFlush ends the flush on all reporters implementing [BatchReporter].
+func (m *multiReporter) EndFlush() {
+	for _, r := range m.rs {
+		if br, ok := r.(BatchReporter); ok {
+			br.EndFlush()
+		}
+	}
+}
+
+// Counter reports a counter value to all reporters.
+func (m *multiReporter) Counter(name string, v int64, tags [][2]string) {
+	for _, r := range m.rs {
+		r.Counter(name, v, tags)
+	}
+}
+
+// SampledCounter reports a sampled counter value with its sample rate to
+// all reporters implementing [SampledReporter], and scaled by the sample
+// rate to the remaining reporters.
+func (m *multiReporter) SampledCounter(name string, v int64, rate float64, tags [][2]string) {
+	for _, r := range m.rs {
+		reportCounter(r, name, v, rate, tags)
+	}
+}
+
+// RemoveCounter removes the counter from all reporters implementing
+// [RemovableReporter].
+func (m *multiReporter) RemoveCounter(name string, tags [][2]string) {
+	for _, r := range m.rs {
+		if rr, ok := r.(RemovableReporter); ok {
+			rr.RemoveCounter(name, tags)
+		}
+	}
+}
+
+// Gauge reports a gauge value to all reporters.
+func (m *multiReporter) Gauge(name string, v float64, tags [][2]string) {
+	for _, r := range m.rs {
+		r.Gauge(name, v, tags)
+	}
+}
+
+// RemoveGauge removes the gauge from all reporters implementing
+// [RemovableReporter].
+func (m *multiReporter) RemoveGauge(name string, tags [][2]string) {
+	for _, r := range m.rs {
+		if rr, ok := r.(RemovableReporter); ok {
+			rr.RemoveGauge(name, tags)
+		}
+	}
+}
+
+// Histogram returns a function observing a value on all reporters
+// implementing [HistogramReporter], or nil if there are none.
+func (m *multiReporter) Histogram(name string, tags [][2]string) func(v float64) {
+	var fns []func(float64)
+	for _, r := range m.rs {
+		hr, ok := r.(HistogramReporter)
+		if !ok {
+			continue
+		}
+		if fn := hr.Histogram(name, tags); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+
+	switch len(fns) {
+	case 0:
+		return nil
+	case 1:
+		return fns[0]
+	default:
+		return func(v float64) {
+			for _, fn := range fns {
+				fn(v)
+			}
+		}
+	}
+}
+
+// RemoveHistogram removes the histogram from all reporters implementing
+// [RemovableHistogramReporter].
+func (m *multiReporter) RemoveHistogram(name string, tags [][2]string) {
+	for _, r := range m.rs {
+		if rr, ok := r.(RemovableHistogramReporter); ok {
+			rr.RemoveHistogram(name, tags)
+		}
+	}
+}
+
+// Timing returns a function observing a duration on all reporters
+// implementing [TimingReporter], or nil if there are none.
+func (m *multiReporter) Timing(name string, tags [][2]string) func(v time.Duration) {
+	var fns []func(time.Duration)
+	for _, r := range m.rs {
+		tr, ok := r.(TimingReporter)
+		if !ok {
+			continue
+		}
+		if fn := tr.Timing(name, tags); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+
+	switch len(fns) {
+	case 0:
+		return nil
+	case 1:
+		return fns[0]
+	default:
+		return func(v time.Duration) {
+			for _, fn := range fns {
+				fn(v)
+			}
+		}
+	}
+}
+
+// HistogramWithExemplar returns a function observing a value with an
+// exemplar on all reporters implementing [ExemplarReporter], and without
+// it on the remaining reporters implementing [HistogramReporter]. It
+// returns nil if no reporter handles exemplars.
+func (m *multiReporter) HistogramWithExemplar(name string, tags [][2]string) func(v float64, exemplar [][2]string) {
+	var (
+		fns []func(float64, [][2]string)
+		ex  bool
+	)
+	for _, r := range m.rs {
+		if er, ok := r.(ExemplarReporter); ok {
+			if fn := er.HistogramWithExemplar(name, tags); fn != nil {
+				fns = append(fns, fn)
+				ex = true
+			}
+			continue
+		}
+		if hr, ok := r.(HistogramReporter); ok {
+			if fn := hr.Histogram(name, tags); fn != nil {
+				fns = append(fns, func(v float64, _ [][2]string) { fn(v) })
+			}
+		}
+	}
+
+	if !ex {
+		return nil
+	}
+	return func(v float64, exemplar [][2]string) {
+		for _, fn := range fns {
+			fn(v, exemplar)
+		}
+	}
+}
+
+// TimingWithExemplar returns a function observing a duration with an
+// exemplar on all reporters implementing [ExemplarReporter], and without
+// it on the remaining reporters implementing [TimingReporter]. It returns
+// nil if no reporter handles exemplars.
+func (m *multiReporter) TimingWithExemplar(name string, tags [][2]string) func(v time.Duration, exemplar [][2]string) {
+	var (
+		fns []func(time.Duration, [][2]string)
+		ex  bool
+	)
+	for _, r := range m.rs {
+		if er, ok := r.(ExemplarReporter); ok {
+			if fn := er.TimingWithExemplar(name, tags); fn != nil {
+				fns = append(fns, fn)
+				ex = true
+			}
+			continue
+		}
+		if tr, ok := r.(TimingReporter); ok {
+			if fn := tr.Timing(name, tags); fn != nil {
+				fns = append(fns, func(v time.Duration, _ [][2]string) { fn(v) })
+			}
+		}
+	}
+
+	if !ex {
+		return nil
+	}
+	return func(v time.Duration, exemplar [][2]string) {
+		for _, fn := range fns {
+			fn(v, exemplar)
+		}
+	}
+}
+
+// RemoveTiming removes the timing from all reporters implementing
+// [RemovableTimingReporter].
+func (m *multiReporter) RemoveTiming(name string, tags [][2]string) {
+	for _, r := range m.rs {
+		if rr, ok := r.(RemovableTimingReporter); ok {
+			rr.RemoveTiming(name, tags)
+		}
+	}
+}
+
+// Set returns a function adding a value to the set on all reporters
+// implementing [SetReporter], or nil if there are none.
+func (m *multiReporter) Set(name string, tags [][2]string) func(v string) {
+	var fns []func(string)
+	for _, r := range m.rs {
+		sr, ok := r.(SetReporter)
+		if !ok {
+			continue
+		}
+		if fn := sr.Set(name, tags); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+
+	switch len(fns) {
+	case 0:
+		return nil
+	case 1:
+		return fns[0]
+	default:
+		return func(v string) {
+			for _, fn := range fns {
+				fn(v)
+			}
+		}
+	}
+}
+
+// RemoveSet removes the set from all reporters implementing
+// [RemovableSetReporter].
+func (m *multiReporter) RemoveSet(name string, tags [][2]string) {
+	for _, r := range m.rs {
+		if rr, ok := r.(RemovableSetReporter); ok {
+			rr.RemoveSet(name, tags)
+		}
+	}
+}
+
+// Distribution returns a function observing a value on all reporters
+// implementing [DistributionReporter], or nil if there are none.
+func (m *multiReporter) Distribution(name string, tags [][2]string) func(v float64) {
+	var fns []func(float64)
+	for _, r := range m.rs {
+		dr, ok := r.(DistributionReporter)
+		if !ok {
+			continue
+		}
+		if fn := dr.Distribution(name, tags); fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+
+	switch len(fns) {
+	case 0:
+		return nil
+	case 1:
+		return fns[0]
+	default:
+		return func(v float64) {
+			for _, fn := range fns {
+				fn(v)
+			}
+		}
+	}
+}
+
+// RemoveDistribution removes the distribution from all reporters
+// implementing [RemovableDistributionReporter].
+func (m *multiReporter) RemoveDistribution(name string, tags [][2]string) {
+	for _, r := range m.rs {
+		if rr, ok := r.(RemovableDistributionReporter); ok {
+			rr.RemoveDistribution(name, tags)
+		}
+	}
+}
+
+// GaugeFunc registers the gauge function with all reporters implementing
+// [GaugeFuncReporter].
+func (m *multiReporter) GaugeFunc(name string, fn func() float64, tags [][2]string) {
+	for _, r := range m.rs {
+		if gr, ok := r.(GaugeFuncReporter); ok {
+			gr.GaugeFunc(name, fn, tags)
+		}
+	}
+}
+
+// RemoveGaugeFunc removes the gauge function from all reporters
+// implementing [GaugeFuncReporter].
+func (m *multiReporter) RemoveGaugeFunc(name string, tags [][2]string) {
+	for _, r := range m.rs {
+		if gr, ok := r.(GaugeFuncReporter); ok {
+			gr.RemoveGaugeFunc(name, tags)
+		}
+	}
+}
+
+// Close closes all reporters implementing [io.Closer], returning
+// the combined errors.
+func (m *multiReporter) Close() error {
+	var errs []error
+	for _, r := range m.rs {
+		if c, ok := r.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isHistogramReporter(r Reporter) bool {
+	return supports[HistogramReporter](r)
+}
+
+func isTimingReporter(r Reporter) bool {
+	return supports[TimingReporter](r)
+}
+
+func isSetReporter(r Reporter) bool {
+	return supports[SetReporter](r)
+}
+
+func isDistributionReporter(r Reporter) bool {
+	return supports[DistributionReporter](r)
+}
+
+func isGaugeFuncReporter(r Reporter) bool {
+	return supports[GaugeFuncReporter](r)
+}
+
+// supports determines if r supports the optional interface T, looking
+// through wrapping reporters. A multi reporter supports T only when all
+// of its reporters do.
+func supports[T any](r Reporter) bool {
+	if _, ok := r.(T); !ok {
+		return false
+	}
+
+	switch r := r.(type) {
+	case *multiReporter:
+		for _, rr := range r.rs {
+			if !supports[T](rr) {
+				return false
+			}
+		}
+		return true
+	case WrappingReporter:
+		return supports[T](r.Unwrap())
+	default:
+		return true
+	}
+}
@@ -0,0 +1,262 @@
+package statter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiReporter(t *testing.T) {
+	r := statter.MultiReporter(&mockSimpleReporter{}, &mockComplexReporter{})
+
+	assert.Implements(t, (*statter.Reporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableReporter)(nil), r)
+	assert.Implements(t, (*statter.HistogramReporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableHistogramReporter)(nil), r)
+	assert.Implements(t, (*statter.TimingReporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableTimingReporter)(nil), r)
+	assert.Implements(t, (*statter.ErrorReporter)(nil), r)
+}
+
+func TestMultiReporter_CounterGauge(t *testing.T) {
+	m1 := &mockSimpleReporter{}
+	m1.On("Counter", "test", int64(2), [][2]string{{"tag", "test"}})
+	m1.On("Gauge", "test", 1.23, [][2]string{{"tag", "test"}})
+	m2 := &mockComplexReporter{}
+	m2.On("Counter", "test", int64(2), [][2]string{{"tag", "test"}})
+	m2.On("Gauge", "test", 1.23, [][2]string{{"tag", "test"}})
+
+	stats := statter.New(statter.MultiReporter(m1, m2), time.Second)
+
+	stats.Counter("test", tags.Str("tag", "test")).Inc(2)
+	stats.Gauge("test", tags.Str("tag", "test")).Set(1.23)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m1.AssertExpectations(t)
+	m2.AssertExpectations(t)
+}
+
+func TestMultiReporter_HistogramSplitsNativeAndAggregated(t *testing.T) {
+	m1 := &mockSimpleReporter{}
+	m1.On("Counter", "test_count", int64(1), [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_sum", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_mean", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_stddev", 0.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_min", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_max", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_10p", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_90p", 10.0, [][2]string{{"tag", "test"}}).Once()
+	var got []float64
+	m2 := &mockComplexReporter{}
+	m2.On("Histogram", "test", [][2]string{{"tag", "test"}}).Return(func(v float64) {
+		got = append(got, v)
+	})
+
+	stats := statter.New(statter.MultiReporter(m1, m2), time.Second)
+
+	stats.Histogram("test", tags.Str("tag", "test")).Observe(10)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{10}, got)
+	m1.AssertExpectations(t)
+	m2.AssertExpectations(t)
+}
+
+func TestMultiReporter_HistogramSplitsNestedReporters(t *testing.T) {
+	plain := &mockSimpleReporter{}
+	plain.On("Counter", "test_count", int64(1), [][2]string{}).Once()
+	plain.On("Gauge", mock.Anything, 10.0, [][2]string{})
+	plain.On("Gauge", "test_stddev", 0.0, [][2]string{}).Once()
+	var got []float64
+	native := &mockComplexReporter{}
+	native.On("Histogram", "test", [][2]string{}).Return(func(v float64) {
+		got = append(got, v)
+	})
+
+	stats := statter.New(statter.MultiReporter(statter.MultiReporter(plain), native), 0)
+
+	stats.Histogram("test").Observe(10)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{10}, got)
+	plain.AssertExpectations(t)
+}
+
+func TestMultiReporter_HistogramSplitsWrappedReporters(t *testing.T) {
+	plain := &mockSimpleReporter{}
+	plain.On("Counter", "test_count", int64(1), [][2]string{}).Once()
+	plain.On("Gauge", mock.Anything, 10.0, [][2]string{})
+	plain.On("Gauge", "test_stddev", 0.0, [][2]string{}).Once()
+	var got []float64
+	native := &mockComplexReporter{}
+	native.On("Histogram", "test", [][2]string{}).Return(func(v float64) {
+		got = append(got, v)
+	})
+
+	stats := statter.New(statter.MultiReporter(&wrappingReporter{r: plain}, native), 0)
+
+	stats.Histogram("test").Observe(10)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{10}, got)
+	plain.AssertExpectations(t)
+}
+
+func TestMultiReporter_TimingSplitsNativeAndAggregated(t *testing.T) {
+	m1 := &mockSimpleReporter{}
+	m1.On("Counter", "test_count", int64(1), [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_sum_ms", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_mean_ms", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_stddev_ms", 0.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_min_ms", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_max_ms", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_10p_ms", 10.0, [][2]string{{"tag", "test"}}).Once()
+	m1.On("Gauge", "test_90p_ms", 10.0, [][2]string{{"tag", "test"}}).Once()
+	var got []time.Duration
+	m2 := &mockComplexReporter{}
+	m2.On("Timing", "test", [][2]string{{"tag", "test"}}).Return(func(v time.Duration) {
+		got = append(got, v)
+	})
+
+	stats := statter.New(statter.MultiReporter(m1, m2), time.Second)
+
+	stats.Timing("test", tags.Str("tag", "test")).Observe(10 * time.Millisecond)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []time.Duration{10 * time.Millisecond}, got)
+	m1.AssertExpectations(t)
+	m2.AssertExpectations(t)
+}
+
+func TestMultiReporter_HistogramDelete(t *testing.T) {
+	m1 := &mockRemovableReporter{}
+	for _, k := range []string{"test_count", "test_sum", "test_mean", "test_stddev", "test_min", "test_max", "test_10p", "test_90p"} {
+		m1.On("RemoveGauge", k, [][2]string{{"tag", "test"}}).Once()
+	}
+	m2 := &mockComplexReporter{}
+	m2.On("Histogram", "test", [][2]string{{"tag", "test"}}).Return(func(float64) {})
+	m2.On("RemoveHistogram", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(statter.MultiReporter(m1, m2), time.Second)
+
+	stats.Histogram("test", tags.Str("tag", "test")).Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m1.AssertExpectations(t)
+	m2.AssertExpectations(t)
+}
+
+func TestMultiReporter_CounterDelete(t *testing.T) {
+	m1 := &mockSimpleReporter{}
+	m2 := &mockComplexReporter{}
+	m2.On("RemoveCounter", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(statter.MultiReporter(m1, m2), time.Second)
+
+	stats.Counter("test", tags.Str("tag", "test")).Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m1.AssertExpectations(t)
+	m2.AssertExpectations(t)
+}
+
+func TestMultiReporter_CloseCombinesErrors(t *testing.T) {
+	err1 := errors.New("test error 1")
+	err2 := errors.New("test error 2")
+
+	r := statter.MultiReporter(&closeErrReporter{err: err1}, &mockSimpleReporter{}, &closeErrReporter{err: err2})
+
+	stats := statter.New(r, time.Second)
+
+	err := stats.Close()
+
+	assert.ErrorIs(t, err, err1)
+	assert.ErrorIs(t, err, err2)
+}
+
+func TestMultiReporter_ErrorsCarryReporter(t *testing.T) {
+	er := &errorReporter{err: errors.New("test error")}
+
+	var got error
+	stats := statter.New(statter.MultiReporter(statter.DiscardReporter, er), time.Second, statter.WithErrorHandler(func(err error) {
+		got = err
+	}))
+
+	stats.Counter("test").Inc(1)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	var repErr *statter.ReporterError
+	require.ErrorAs(t, got, &repErr)
+	assert.Same(t, er, repErr.Reporter)
+	assert.Equal(t, "test", repErr.Name)
+	assert.ErrorIs(t, got, er.err)
+}
+
+type mockRemovableReporter struct {
+	mockSimpleReporter
+}
+
+func (r *mockRemovableReporter) RemoveCounter(name string, tags [][2]string) {
+	_ = r.Called(name, tags)
+}
+
+func (r *mockRemovableReporter) RemoveGauge(name string, tags [][2]string) {
+	_ = r.Called(name, tags)
+}
+
+type closeErrReporter struct {
+	discardReporter
+
+	err error
+}
+
+func (r *closeErrReporter) Close() error {
+	return r.err
+}
+
+// wrappingReporter wraps a reporter, claiming to handle histograms
+// whether the wrapped reporter does or not.
+type wrappingReporter struct {
+	r statter.Reporter
+}
+
+func (r *wrappingReporter) Unwrap() statter.Reporter {
+	return r.r
+}
+
+func (r *wrappingReporter) Counter(name string, v int64, tags [][2]string) {
+	r.r.Counter(name, v, tags)
+}
+
+func (r *wrappingReporter) Gauge(name string, v float64, tags [][2]string) {
+	r.r.Gauge(name, v, tags)
+}
+
+func (r *wrappingReporter) Histogram(name string, tags [][2]string) func(v float64) {
+	if hr, ok := r.r.(statter.HistogramReporter); ok {
+		return hr.Histogram(name, tags)
+	}
+	return nil
+}
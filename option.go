@@ -0,0 +1,138 @@
+package statter
+
+// MetricOption configures a metric when it is created, see [Statter.Options].
+type MetricOption func(metricOptions) metricOptions
+
+// Help returns a metric option that sets the help text of a metric.
+func Help(text string) MetricOption {
+	return func(o metricOptions) metricOptions {
+		o.meta.Help = text
+		return o
+	}
+}
+
+// Unit returns a metric option that sets the unit of a metric, such as
+// "seconds" or "bytes".
+func Unit(unit string) MetricOption {
+	return func(o metricOptions) metricOptions {
+		o.meta.Unit = unit
+		return o
+	}
+}
+
+// SampleRate returns a metric option that records only the given fraction
+// of the events of a metric, between 0 and 1, scaling the reported stats
+// back up so they stay unbiased. Sampling trades precision for a lower
+// cost on hot paths.
+//
+// It applies to counters, and to histograms and timings aggregated
+// locally. Delegated histograms and timings are never sampled, as their
+// observations cannot be scaled. Rates outside of (0, 1) disable sampling.
+func SampleRate(rate float64) MetricOption {
+	return func(o metricOptions) metricOptions {
+		if rate <= 0 || rate >= 1 {
+			rate = 0
+		}
+		o.rate = rate
+		return o
+	}
+}
+
+// Metadata describes a metric.
+type Metadata struct {
+	Help string
+	Unit string
+}
+
+// DescribingReporter represents a stats reporter that handles metric metadata.
+//
+// Describe is called with the metadata of a metric before the metric is
+// created, for every metric created with at least one descriptive option.
+type DescribingReporter interface {
+	Describe(name string, meta Metadata)
+}
+
+// metricOptions are the options of a metric.
+type metricOptions struct {
+	meta Metadata
+	// rate is the sample rate, or zero if unsampled.
+	rate float64
+}
+
+// Options returns a view of the statter creating metrics with the given
+// options. The options are not part of the metric tags, and only take
+// effect when a metric is created; lookups of existing metrics return
+// them unchanged.
+func (s *Statter) Options(opts ...MetricOption) MetricBuilder {
+	b := MetricBuilder{s: s}
+	for _, opt := range opts {
+		b.opts = opt(b.opts)
+	}
+	return b
+}
+
+// MetricBuilder creates metrics with metric options, see [Statter.Options].
+type MetricBuilder struct {
+	s    *Statter
+	opts metricOptions
+}
+
+// Counter returns a counter, see [Statter.Counter].
+func (b MetricBuilder) Counter(name string, tags ...Tag) *Counter {
+	return b.s.counter(name, tags, b.opts)
+}
+
+// Gauge returns a gauge, see [Statter.Gauge].
+func (b MetricBuilder) Gauge(name string, tags ...Tag) *Gauge {
+	return b.s.gauge(name, tags, b.opts)
+}
+
+// Histogram returns a histogram, see [Statter.Histogram].
+func (b MetricBuilder) Histogram(name string, tags ...Tag) *Histogram {
+	return b.s.histogram(name, tags, b.opts)
+}
+
+// Timing returns a timing, see [Statter.Timing].
+func (b MetricBuilder) Timing(name string, tags ...Tag) *Timing {
+	return b.s.timing(name, tags, b.opts)
+}
+
+// Set returns a set, see [Statter.Set].
+func (b MetricBuilder) Set(name string, tags ...Tag) *Set {
+	return b.s.set(name, tags, b.opts)
+}
+
+// Distribution returns a distribution, see [Statter.Distribution].
+func (b MetricBuilder) Distribution(name string, tags ...Tag) *Distribution {
+	return b.s.distribution(name, tags, b.opts)
+}
+
+// GaugeFunc registers a gauge function, see [Statter.GaugeFunc].
+func (b MetricBuilder) GaugeFunc(name string, fn func() float64, tags ...Tag) *GaugeFunc {
+	return b.s.gaugeFunc(name, fn, tags, b.opts)
+}
+
+// CounterFunc registers a counter function, see [Statter.CounterFunc].
+func (b MetricBuilder) CounterFunc(name string, fn func() int64, tags ...Tag) *CounterFunc {
+	return b.s.counterFunc(name, fn, tags, b.opts)
+}
+
+// CounterVec returns a vector of counters, see [Statter.CounterVec].
+func (b MetricBuilder) CounterVec(name string, keys ...string) *CounterVec {
+	return &CounterVec{vec: newVec(b.s, name, keys, b.Counter, b.s.HasCounter)}
+}
+
+// GaugeVec returns a vector of gauges, see [Statter.GaugeVec].
+func (b MetricBuilder) GaugeVec(name string, keys ...string) *GaugeVec {
+	return &GaugeVec{vec: newVec(b.s, name, keys, b.Gauge, b.s.HasGauge)}
+}
+
+// HistogramVec returns a vector of histograms, see [Statter.HistogramVec].
+func (b MetricBuilder) HistogramVec(name string, keys ...string) *HistogramVec {
+	return &HistogramVec{vec: newVec(b.s, name, keys, b.Histogram, b.s.HasHistogram)}
+}
+
+// TimingVec returns a vector of timings, see [Statter.TimingVec].
+func (b MetricBuilder) TimingVec(name string, keys ...string) *TimingVec {
+	return &TimingVec{vec: newVec(b.s, name, keys, b.Timing, b.s.HasTiming)}
+}
@@ -0,0 +1,79 @@
+package statter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_MetricOptionsAreNotTags(t *testing.T) {
+	m := &mockDescribingReporter{}
+	m.On("Describe", "test", statter.Metadata{Help: "my counter", Unit: "requests"}).Once()
+	m.On("Counter", "test", int64(2), [][2]string{{"tag", "test"}})
+
+	stats := statter.New(m, time.Second)
+
+	c := stats.Options(statter.Help("my counter"), statter.Unit("requests")).Counter("test", tags.Str("tag", "test"))
+	c.Inc(1)
+	stats.Counter("test", tags.Str("tag", "test")).Inc(1)
+
+	assert.Same(t, c, stats.Counter("test", tags.Str("tag", "test")))
+	assert.Same(t, c, stats.Options(statter.Help("other")).Counter("test", tags.Str("tag", "test")))
+	assert.True(t, stats.HasCounter("test", tags.Str("tag", "test")))
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_MetricOptionsDescribeAllTypes(t *testing.T) {
+	m := &mockDescribingReporter{}
+	m.On("Describe", "gauge", statter.Metadata{Help: "my gauge"}).Once()
+	m.On("Describe", "histo", statter.Metadata{Unit: "bytes"}).Once()
+	m.On("Describe", "timing", statter.Metadata{Help: "my timing", Unit: "seconds"}).Once()
+	m.On("Counter", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	m.On("Gauge", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	stats := statter.New(m, time.Second)
+
+	stats.Options(statter.Help("my gauge")).Gauge("gauge")
+	stats.Options(statter.Unit("bytes")).Histogram("histo")
+	stats.Options(statter.Help("my timing"), statter.Unit("seconds")).Timing("timing")
+	stats.Counter("counter")
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_OptionsDescribeVectors(t *testing.T) {
+	m := &mockDescribingReporter{}
+	m.On("Describe", "prefix.test", statter.Metadata{Help: "my counter"}).Once()
+	m.On("Counter", "prefix.test", int64(2), [][2]string{{"base", "val"}, {"code", "200"}})
+
+	stats := statter.New(m, time.Second)
+
+	v := stats.With("prefix", tags.Str("base", "val")).Options(statter.Help("my counter")).CounterVec("test", "code")
+	v.With("200").Inc(1)
+	v.With("200").Inc(1)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+type mockDescribingReporter struct {
+	mockSimpleReporter
+}
+
+func (r *mockDescribingReporter) Describe(name string, meta statter.Metadata) {
+	_ = r.Called(name, meta)
+}
@@ -0,0 +1,37 @@
+package statter
+
+import "context"
+
+// Pause stops the reporting loop from reporting stats, for example while
+// the backend of the reporter is known to be down. Stats keep accumulating
+// while paused: counters accumulate their deltas, and aggregated histograms
+// and timings keep their bounded samples. Series are not expired while
+// paused.
+//
+// Flush and Close still report the pending stats while paused. Pause
+// applies to the whole statter tree and may be called on any statter,
+// including sub-statters.
+func (s *Statter) Pause() {
+	s.reg.paused.Store(true)
+}
+
+// Resume resumes reporting stats after Pause, synchronously reporting the
+// stats accumulated while paused. Resume is a no-op if the statter is not
+// paused or is closed.
+func (s *Statter) Resume() {
+	s.reg.Resume()
+}
+
+// Paused determines if reporting is paused.
+func (s *Statter) Paused() bool {
+	return s.reg.paused.Load()
+}
+
+// Resume unpauses the registry, reporting the pending stats.
+func (r *registry) Resume() {
+	if !r.paused.CompareAndSwap(true, false) || r.closed.Load() {
+		return
+	}
+
+	_ = r.flush(context.Background(), r.cfg.clock.Now())
+}
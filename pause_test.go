@@ -0,0 +1,98 @@
+package statter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_PauseAccumulatesUntilResumed(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(3), [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 10*time.Second, statter.WithClock(clock))
+
+	stats.Pause()
+	assert.True(t, stats.Paused())
+
+	c := stats.Counter("test", tags.Str("tag", "test"))
+	for range 3 {
+		c.Inc(1)
+
+		clock.BlockUntilTimers(1)
+		clock.Advance(10 * time.Second)
+	}
+	clock.BlockUntilTimers(1)
+
+	m.AssertNotCalled(t, "Counter", "test", int64(1), [][2]string{{"tag", "test"}})
+
+	stats.Resume()
+	assert.False(t, stats.Paused())
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_PauseStillFlushes(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	stats.Pause()
+	stats.Counter("test").Inc(1)
+	r.Flush(t)
+
+	r.AssertCounter(t, "test", nil, 1)
+}
+
+func TestStatter_PauseReportsOnClose(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(1), [][2]string{}).Once()
+
+	stats := statter.New(m, time.Hour)
+
+	stats.Pause()
+	stats.Counter("test").Inc(1)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_ResumeWhenNotPaused(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(1), [][2]string{}).Once()
+
+	stats := statter.New(m, 0)
+
+	stats.Counter("test").Inc(1)
+	stats.Resume()
+
+	m.AssertNotCalled(t, "Counter", "test", int64(1), [][2]string{})
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_PauseOnSubStatter(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	sub := stats.With("sub")
+	sub.Pause()
+
+	assert.True(t, stats.Paused())
+
+	sub.Counter("test").Inc(1)
+	stats.Resume()
+
+	r.AssertCounter(t, "sub.test", nil, 1)
+}
@@ -1,59 +1,186 @@
 package statter
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go4org/hashtriemap"
 	"github.com/hamba/statter/v2/internal/stats"
 )
 
+// overflowTagValue is the tag value of series redirected
+// to the overflow series once a series limit is reached.
+const overflowTagValue = "overflow"
+
+// maxAliases is the maximum number of series keys aliased to the
+// key of another series.
+const maxAliases = 1 << 14
+
 type registry struct {
 	r    Reporter
 	hr   HistogramReporter
 	tr   TimingReporter
+	sr   SetReporter
+	dr   DistributionReporter
 	pool *stats.Pool
 	cfg  config
 
-	counters   hashtriemap.HashTrieMap[string, *Counter]
-	gauges     hashtriemap.HashTrieMap[string, *Gauge]
-	histograms hashtriemap.HashTrieMap[string, *Histogram]
-	timings    hashtriemap.HashTrieMap[string, *Timing]
+	// ha, ta, sa and da receive locally aggregated histograms, timings,
+	// sets and distributions.
+	ha Reporter
+	ta Reporter
+	sa Reporter
+	da Reporter
+	// splitHist, splitTiming, splitSet and splitDist are set when only some
+	// reporters handle histograms, timings, sets or distributions natively,
+	// so observations are both delegated and aggregated locally.
+	splitHist   bool
+	splitTiming bool
+	splitSet    bool
+	splitDist   bool
+
+	// gfr evaluates gauge functions itself, while gfa receives gauge
+	// function values on each report. Either may be nil.
+	gfr GaugeFuncReporter
+	gfa Reporter
+
+	counters      hashtriemap.HashTrieMap[string, *Counter]
+	gauges        hashtriemap.HashTrieMap[string, *Gauge]
+	histograms    hashtriemap.HashTrieMap[string, *Histogram]
+	timings       hashtriemap.HashTrieMap[string, *Timing]
+	gaugeFuncs    hashtriemap.HashTrieMap[string, *GaugeFunc]
+	counterFuncs  hashtriemap.HashTrieMap[string, *CounterFunc]
+	sets          hashtriemap.HashTrieMap[string, *Set]
+	distributions hashtriemap.HashTrieMap[string, *Distribution]
+
+	series     atomic.Int64
+	nameSeries hashtriemap.HashTrieMap[string, *atomic.Int64]
+	dropped    atomic.Int64
+	// aliases maps the keys of series resolved to a series under another
+	// key, such as dropped series to their overflow series, to that key.
+	// It holds up to maxAliases keys. Overflow aliases are removed when a
+	// series is removed, so that dropped series may be admitted again.
+	aliases         hashtriemap.HashTrieMap[string, seriesAlias]
+	aliasCount      atomic.Int64
+	overflowAliases atomic.Int64
 
 	mu       sync.RWMutex
 	root     *Statter
 	statters map[string]*Statter
 
-	done chan struct{}
-	wg   sync.WaitGroup
+	// nop metrics are handed out once the registry is closed.
+	nopCounter      *Counter
+	nopGauge        *Gauge
+	nopHistogram    *Histogram
+	nopTiming       *Timing
+	nopGaugeFunc    *GaugeFunc
+	nopCounterFunc  *CounterFunc
+	nopSet          *Set
+	nopDistribution *Distribution
+
+	flushing chan struct{}
+	paused   atomic.Bool
+	closed   atomic.Bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	// lastFlush is the timestamp of the previous flush, guarded by flushing.
+	lastFlush time.Time
 }
 
 func newRegistry(root *Statter, r Reporter, interval time.Duration, cfg config) *registry {
 	reg := &registry{
 		r:        r,
+		ha:       r,
+		ta:       r,
+		sa:       r,
+		da:       r,
+		gfa:      r,
 		pool:     stats.NewPool(cfg.percSamples),
 		cfg:      cfg,
 		root:     root,
 		statters: map[string]*Statter{},
+		flushing: make(chan struct{}, 1),
 		done:     make(chan struct{}),
 	}
+	reg.lastFlush = cfg.clock.Now()
 
-	if hr, ok := r.(HistogramReporter); ok {
+	if hr, ok := r.(HistogramReporter); ok && isHistogramReporter(r) {
 		reg.hr = hr
 	}
-	if tr, ok := r.(TimingReporter); ok {
+	if tr, ok := r.(TimingReporter); ok && isTimingReporter(r) {
 		reg.tr = tr
 	}
+	if sr, ok := r.(SetReporter); ok && isSetReporter(r) {
+		reg.sr = sr
+	}
+	if dr, ok := r.(DistributionReporter); ok && isDistributionReporter(r) {
+		reg.dr = dr
+	}
+	if gfr, ok := r.(GaugeFuncReporter); ok && isGaugeFuncReporter(r) {
+		reg.gfr, reg.gfa = gfr, nil
+	}
+	if mr, ok := r.(*multiReporter); ok {
+		if in, out := mr.split(isHistogramReporter); in != nil && out != nil {
+			reg.hr, reg.ha, reg.splitHist = in, out, true
+		}
+		if in, out := mr.split(isTimingReporter); in != nil && out != nil {
+			reg.tr, reg.ta, reg.splitTiming = in, out, true
+		}
+		if in, out := mr.split(isSetReporter); in != nil && out != nil {
+			reg.sr, reg.sa, reg.splitSet = in, out, true
+		}
+		if in, out := mr.split(isDistributionReporter); in != nil && out != nil {
+			reg.dr, reg.da, reg.splitDist = in, out, true
+		}
+
+		reg.gfr, reg.gfa = nil, nil
+		in, out := mr.split(isGaugeFuncReporter)
+		if in != nil {
+			reg.gfr = in
+		}
+		if out != nil {
+			reg.gfa = out
+		}
+	}
+
+	if er, ok := r.(ErrorReporter); ok && cfg.errHandler != nil {
+		er.OnError(func(name string, tags [][2]string, err error) {
+			// Errors already attributed to a reporter, such as those
+			// raised through a multi reporter, are passed as is.
+			var repErr *ReporterError
+			if errors.As(err, &repErr) {
+				reg.handleError(err)
+				return
+			}
+			reg.handleError(&ReporterError{Reporter: r, Name: name, Tags: tags, Err: err})
+		})
+	}
+
+	reg.nopCounter = &Counter{reg: reg}
+	reg.nopGauge = &Gauge{reg: reg}
+	reg.nopHistogram = &Histogram{hrFn: func(float64) {}, reg: reg}
+	reg.nopTiming = &Timing{trFn: func(time.Duration) {}, reg: reg}
+	reg.nopGaugeFunc = &GaugeFunc{reg: reg}
+	reg.nopCounterFunc = &CounterFunc{reg: reg}
+	reg.nopSet = &Set{srFn: func(string) {}, reg: reg}
+	reg.nopDistribution = &Distribution{drFn: func(float64) {}, reg: reg}
 
 	// Register root statter in the deduplication cache.
 	k := newKey(root.prefix, root.tags)
 	reg.statters[k.SafeString()] = root
 	k.Release()
 
-	reg.wg.Add(1)
-	go reg.runReportLoop(interval)
+	// A non-positive interval puts the registry in manual mode,
+	// where reporting is only driven by Flush and Close.
+	if interval > 0 {
+		reg.wg.Add(1)
+		go reg.runReportLoop(interval)
+	}
 
 	return reg
 }
@@ -61,26 +188,89 @@ func newRegistry(root *Statter, r Reporter, interval time.Duration, cfg config)
 func (r *registry) runReportLoop(d time.Duration) {
 	defer r.wg.Done()
 
-	tick := time.NewTicker(d)
-	defer tick.Stop()
+	clock := r.cfg.clock
+
+	next := r.firstFlush(clock.Now(), d)
+	timer := clock.NewTimer(next.Sub(clock.Now()) + r.jitter())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-r.done:
 			return
-		case <-tick.C:
+		case <-timer.C():
 		}
 
-		r.report()
+		// Stats accumulate while paused, until resumed.
+		if !r.paused.Load() {
+			_ = r.flush(context.Background(), next)
+		}
+
+		now := clock.Now()
+		next = nextFlush(next, now, d)
+		timer.Reset(next.Sub(now) + r.jitter())
+	}
+}
+
+// Flush reports all pending stats, waiting for any in-progress
+// report to complete first.
+func (r *registry) Flush(ctx context.Context) error {
+	if r.closed.Load() {
+		return ErrClosed
+	}
+
+	return r.flush(ctx, r.cfg.clock.Now())
+}
+
+// flush reports all pending stats with the flush timestamp ts.
+func (r *registry) flush(ctx context.Context, ts time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	select {
+	case r.flushing <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+	defer func() { <-r.flushing }()
+
+	r.report(ts)
+	if r.cfg.seriesTTL > 0 {
+		r.expire(r.cfg.clock.Now())
+	}
+
+	return nil
 }
 
-func (r *registry) report() {
+func (r *registry) report(ts time.Time) {
+	// A scheduled flush firing late, such as with jitter, may follow
+	// a flush triggered by Flush or Resume with a later timestamp.
+	ts = later(ts, r.lastFlush)
+	interval := ts.Sub(r.lastFlush)
+	r.lastFlush = ts
+
+	if fr, ok := r.r.(FlushReporter); ok {
+		fr.BeginFlush(ts, interval)
+	}
+	if br, ok := r.r.(BatchReporter); ok {
+		defer br.EndFlush()
+	}
+
 	r.counters.Range(func(_ string, c *Counter) bool {
 		val := c.value()
 		if val == 0 {
 			return true
 		}
+		reportCounter(r.r, c.name, val, c.rate, c.tags)
+		return true
+	})
+
+	r.counterFuncs.Range(func(_ string, c *CounterFunc) bool {
+		val, ok := c.delta()
+		if !ok || val == 0 {
+			return true
+		}
 		r.r.Counter(c.name, val, c.tags)
 		return true
 	})
@@ -90,45 +280,200 @@ func (r *registry) report() {
 		return true
 	})
 
-	if r.hr == nil {
-		r.histograms.Range(func(_ string, h *Histogram) bool {
-			histo := h.value()
-			defer r.pool.Put(histo)
-			r.reportSample(h.name, "", h.tags, histo)
+	if r.gfa != nil {
+		r.gaugeFuncs.Range(func(_ string, g *GaugeFunc) bool {
+			if v, ok := g.value(); ok {
+				r.gfa.Gauge(g.name, v, g.tags)
+			}
 			return true
 		})
 	}
 
-	if r.tr == nil {
-		r.timings.Range(func(_ string, t *Timing) bool {
-			timing := t.value()
-			defer r.pool.Put(timing)
-			r.reportSample(t.name, "_ms", t.tags, timing)
+	r.histograms.Range(func(_ string, h *Histogram) bool {
+		if h.s == nil {
 			return true
-		})
+		}
+		histo := h.value()
+		defer r.pool.Put(histo)
+		r.reportSample(r.ha, h.name, "", h.tags, histo, h.rate)
+		return true
+	})
+
+	r.timings.Range(func(_ string, t *Timing) bool {
+		if t.s == nil {
+			return true
+		}
+		timing := t.value()
+		defer r.pool.Put(timing)
+		r.reportSample(r.ta, t.name, "_ms", t.tags, timing, t.rate)
+		return true
+	})
+
+	r.sets.Range(func(_ string, s *Set) bool {
+		if s.s == nil {
+			return true
+		}
+		r.sa.Gauge(s.name, float64(s.value()), s.tags)
+		return true
+	})
+
+	r.distributions.Range(func(_ string, d *Distribution) bool {
+		if d.counts == nil {
+			return true
+		}
+		d.report(r.da)
+		return true
+	})
+}
+
+// admit reserves a series for name, returning false if a series
+// limit has been reached.
+func (r *registry) admit(name string) bool {
+	if r.cfg.maxSeries > 0 && r.series.Add(1) > int64(r.cfg.maxSeries) {
+		r.series.Add(-1)
+		r.dropped.Add(1)
+		return false
+	}
+
+	if r.cfg.maxNameSeries > 0 {
+		n := r.nameCount(name)
+		if n.Add(1) > int64(r.cfg.maxNameSeries) {
+			n.Add(-1)
+			if r.cfg.maxSeries > 0 {
+				r.series.Add(-1)
+			}
+			r.dropped.Add(1)
+			return false
+		}
+	}
+
+	return true
+}
+
+// stored settles the series reservation once a series has been stored,
+// releasing it if an existing series was loaded instead.
+func (r *registry) stored(name string, tags []Tag, overflow, loaded bool) {
+	switch {
+	case overflow && !loaded:
+		r.handleError(&SeriesLimitError{Name: name, Tags: tags})
+	case !overflow && loaded:
+		r.release(name)
+	}
+}
+
+// seriesAlias is the key of the series a series key resolves to.
+type seriesAlias struct {
+	key string
+	// name is the name of a dropped series, aliased to an overflow series.
+	name     string
+	overflow bool
+}
+
+// alias aliases the series key k to the key of another series, unless
+// the maximum number of aliases has been reached.
+func (r *registry) alias(k string, a seriesAlias) {
+	if r.aliasCount.Add(1) > maxAliases {
+		r.aliasCount.Add(-1)
+		return
+	}
+	if _, loaded := r.aliases.LoadOrStore(k, a); loaded {
+		r.aliasCount.Add(-1)
+		return
+	}
+	if a.overflow {
+		r.overflowAliases.Add(1)
+	}
+}
+
+// removed releases the series reservation of a removed series, and removes
+// the overflow aliases of the series it may now be admitted in place of.
+func (r *registry) removed(name string, overflow bool) {
+	if overflow {
+		return
+	}
+
+	r.release(name)
+
+	if r.overflowAliases.Load() == 0 {
+		return
+	}
+	// Under a series limit, any dropped series may now be admitted.
+	// Under a per-name limit only, only those with the same name.
+	anyName := r.cfg.maxSeries > 0
+	r.aliases.Range(func(k string, a seriesAlias) bool {
+		if !a.overflow || (!anyName && a.name != name) {
+			return true
+		}
+		if r.aliases.CompareAndDelete(k, a) {
+			r.aliasCount.Add(-1)
+			r.overflowAliases.Add(-1)
+		}
+		return true
+	})
+}
+
+// release releases the series reservation for name.
+func (r *registry) release(name string) {
+	if r.cfg.maxSeries > 0 {
+		r.series.Add(-1)
+	}
+	if r.cfg.maxNameSeries > 0 {
+		r.nameCount(name).Add(-1)
+	}
+}
+
+func (r *registry) nameCount(name string) *atomic.Int64 {
+	n, ok := r.nameSeries.Load(name)
+	if !ok {
+		n, _ = r.nameSeries.LoadOrStore(name, &atomic.Int64{})
+	}
+	return n
+}
+
+// describe passes the metadata of a new metric to the reporter.
+func (r *registry) describe(name string, meta Metadata) {
+	if meta == (Metadata{}) {
+		return
 	}
+	if dr, ok := r.r.(DescribingReporter); ok {
+		dr.Describe(name, meta)
+	}
+}
+
+func (r *registry) handleError(err error) {
+	if r.cfg.errHandler == nil {
+		return
+	}
+	r.cfg.errHandler(err)
 }
 
-func (r *registry) reportSample(name, suffix string, tags [][2]string, sample *stats.Sample) {
+// reportSample reports the stats of sample, scaling the count and sum
+// of a sampled series back up by its sample rate.
+func (r *registry) reportSample(rep Reporter, name, suffix string, tags [][2]string, sample *stats.Sample, rate float64) {
 	if sample.Count() == 0 {
 		return
 	}
 
 	prefix := name + "_"
-	r.r.Counter(prefix+"count", sample.Count(), tags)
-	r.r.Gauge(prefix+"sum"+suffix, sample.Sum(), tags)
-	r.r.Gauge(prefix+"mean"+suffix, sample.Mean(), tags)
-	r.r.Gauge(prefix+"stddev"+suffix, sample.StdDev(), tags)
-	r.r.Gauge(prefix+"min"+suffix, sample.Min(), tags)
-	r.r.Gauge(prefix+"max"+suffix, sample.Max(), tags)
+	reportAggregateCounter(rep, name, prefix+"count", scale(sample.Count(), rate), tags)
+	reportAggregateGauge(rep, name, prefix+"sum"+suffix, scaleSum(sample.Sum(), rate), tags)
+	reportAggregateGauge(rep, name, prefix+"mean"+suffix, sample.Mean(), tags)
+	reportAggregateGauge(rep, name, prefix+"stddev"+suffix, sample.StdDev(), tags)
+	reportAggregateGauge(rep, name, prefix+"min"+suffix, sample.Min(), tags)
+	reportAggregateGauge(rep, name, prefix+"max"+suffix, sample.Max(), tags)
 	ps := r.cfg.percentiles
 	vs := sample.Percentiles(ps)
 	for i := range vs {
 		n := prefix + strconv.FormatFloat(ps[i], 'g', -1, 64) + "p" + suffix
-		r.r.Gauge(n, vs[i], tags)
+		reportAggregateGauge(rep, name, n, vs[i], tags)
 	}
 }
 
+// newSample returns a sample for a locally aggregated histogram or timing.
+func (r *registry) newSample() *shardedSample {
+	return newShardedSample(r.cfg.shards, r.cfg.percSamples)
+}
+
 func (r *registry) sampleKeys(name, suffix string) []string {
 	prefix := name + "_"
 	keys := make([]string, 0, 6+len(r.cfg.percentiles))
@@ -148,7 +493,7 @@ func (r *registry) sampleKeys(name, suffix string) []string {
 
 // SubStatter returns a unique sub statter.
 func (r *registry) SubStatter(parent *Statter, prefix string, tags []Tag) *Statter {
-	name, newTags := mergeDescriptors(parent.prefix, r.cfg.separator, prefix, parent.tags, tags)
+	name, newTags := mergeDescriptors(parent.prefix, r.cfg.separator, prefix, parent.tags, tags, r.cfg.tagPolicy)
 
 	// Sort merged tags to maintain the sorted-base-tags invariant so that
 	// the mergeDescriptors fast paths in metric accessors are safe.
@@ -167,6 +512,8 @@ func (r *registry) SubStatter(parent *Statter, prefix string, tags []Tag) *Statt
 	r.mu.RUnlock()
 
 	// Slow path: first time we have seen this sub-statter.
+	r.cfg.tagPolicy.report(name, tags)
+
 	s := &Statter{
 		reg:    r,
 		prefix: name,
@@ -186,20 +533,46 @@ func (r *registry) SubStatter(parent *Statter, prefix string, tags []Tag) *Statt
 
 // Close closes the registry if the caller is the root statter,
 // otherwise an error is returned.
-func (r *registry) Close(caller *Statter) error {
+//
+// The registry is marked as closed before the final flush, even if
+// the flush does not complete before ctx is done.
+func (r *registry) Close(ctx context.Context, caller *Statter) error {
 	if caller != r.root {
 		return errors.New("close cannot be called from a sub-statter")
 	}
+	if !r.closed.CompareAndSwap(false, true) {
+		return ErrClosed
+	}
 
 	close(r.done)
-	r.wg.Wait()
 
-	r.report()
+	return runContext(ctx, CloseStageFlush, func() error {
+		r.wg.Wait()
 
-	return nil
+		return r.flush(context.Background(), r.cfg.clock.Now())
+	})
 }
 
-func mergeDescriptors(prefix, sep, name string, baseTags, tags []Tag) (string, []Tag) {
+// runContext runs fn, returning a CloseError for stage if
+// ctx is done before fn returns. In that case fn is left
+// to complete in the background.
+func runContext(ctx context.Context, stage CloseStage, fn func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return &CloseError{Stage: stage, Err: ctx.Err()}
+	}
+}
+
+// mergeDescriptors returns the name and tags of name and tags merged into
+// the prefix and base tags, applying the tag policy p if not nil.
+func mergeDescriptors(prefix, sep, name string, baseTags, tags []Tag, p *tagPolicy) (string, []Tag) {
 	switch {
 	case prefix != "" && name != "":
 		name = prefix + sep + name
@@ -209,13 +582,20 @@ func mergeDescriptors(prefix, sep, name string, baseTags, tags []Tag) (string, [
 
 	newTags := make([]Tag, len(baseTags), len(baseTags)+len(tags))
 	copy(newTags, baseTags)
-	newTags = mergeTags(newTags, tags)
+	newTags = mergeTags(newTags, tags, p)
 
 	return name, newTags
 }
 
-func mergeTags(out, tags []Tag) []Tag {
+// mergeTags merges tags into out, applying the tag policy p if not nil.
+func mergeTags(out, tags []Tag, p *tagPolicy) []Tag {
 	for _, tag := range tags {
+		if p != nil {
+			var v tagViolations
+			if tag, v = p.apply(tag); v&violationKey != 0 {
+				continue
+			}
+		}
 		if i := tagIndex(out, tag[0]); i >= 0 {
 			out[i][1] = tag[1]
 			continue
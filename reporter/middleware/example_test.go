@@ -0,0 +1,23 @@
+package middleware_test
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/reporter/middleware"
+	"github.com/hamba/statter/v2/statstest"
+)
+
+func ExampleNew() {
+	var reporter statter.Reporter = statstest.NewReporter()
+
+	reporter = middleware.New(reporter,
+		middleware.DenyRegexp(regexp.MustCompile(`^debug\.`)),
+		middleware.Rename("http.latency", "http.request.duration"),
+		middleware.DropTags("user_id"),
+		middleware.Prefix("my-app."),
+	)
+
+	statter.New(reporter, 10*time.Second)
+}
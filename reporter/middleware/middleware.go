@@ -0,0 +1,347 @@
+// Package middleware implements reporter middleware, filtering, renaming and
+// rewriting metrics before they reach a reporter.
+package middleware
+
+import (
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/hamba/statter/v2"
+)
+
+// Rule rewrites the name and tags of a metric, returning false to drop
+// the metric. Rules must not modify the given tags in place.
+type Rule func(name string, tags [][2]string) (string, [][2]string, bool)
+
+// New returns a reporter applying the rules in order to every metric before
+// passing it to r. A metric dropped by a rule is not passed to the rules
+// that follow, nor to r.
+//
+// The returned reporter preserves the optional interfaces of r: histograms,
+// timings, sets and distributions not handled by r are aggregated locally by
+// the statter, and removals are only passed to r if it supports them. When r
+// is a [statter.MultiReporter], each of its reporters is wrapped, so the
+// statter still handles each reporter by what it supports.
+//
+// The stats aggregated locally from a metric, such as the latency_count and
+// latency_sum stats of a latency histogram, are matched by the rules on the
+// name of the metric, see [statter.AggregateReporter]. Deny("latency") drops
+// all of them, and Rename("latency", "duration") reports duration_count and
+// duration_sum.
+func New(r statter.Reporter, rules ...Rule) statter.Reporter {
+	if mr, ok := r.(interface{ Unwrap() []statter.Reporter }); ok {
+		rs := mr.Unwrap()
+		wrapped := make([]statter.Reporter, len(rs))
+		for i, r := range rs {
+			wrapped[i] = New(r, rules...)
+		}
+		return statter.MultiReporter(wrapped...)
+	}
+
+	rep := &reporter{r: r, rules: rules}
+	if gfr, ok := r.(statter.GaugeFuncReporter); ok {
+		return &gaugeFuncReporter{reporter: rep, gfr: gfr}
+	}
+	return rep
+}
+
+type reporter struct {
+	r     statter.Reporter
+	rules []Rule
+}
+
+func (r *reporter) apply(name string, tags [][2]string) (string, [][2]string, bool) {
+	for _, rule := range r.rules {
+		var ok bool
+		if name, tags, ok = rule(name, tags); !ok {
+			return "", nil, false
+		}
+	}
+	return name, tags, true
+}
+
+// Unwrap returns the wrapped reporter, see [statter.WrappingReporter].
+func (r *reporter) Unwrap() statter.Reporter {
+	return r.r
+}
+
+// OnError sets the error function on the reporter if it implements
+// [statter.ErrorReporter].
+func (r *reporter) OnError(fn func(name string, tags [][2]string, err error)) {
+	if er, ok := r.r.(statter.ErrorReporter); ok {
+		er.OnError(fn)
+	}
+}
+
+// Describe passes the metric metadata to the reporter if it implements
+// [statter.DescribingReporter]. Rules are applied to the name only.
+func (r *reporter) Describe(name string, meta statter.Metadata) {
+	dr, ok := r.r.(statter.DescribingReporter)
+	if !ok {
+		return
+	}
+	if name, _, ok = r.apply(name, nil); ok {
+		dr.Describe(name, meta)
+	}
+}
+
+// BeginFlush passes the flush timestamp and interval to the reporter if it
+// implements [statter.FlushReporter].
+func (r *reporter) BeginFlush(ts time.Time, interval time.Duration) {
+	if fr, ok := r.r.(statter.FlushReporter); ok {
+		fr.BeginFlush(ts, interval)
+	}
+}
+
+// EndFlush ends the flush on the reporter if it implements
+// [statter.BatchReporter].
+func (r *reporter) EndFlush() {
+	if br, ok := r.r.(statter.BatchReporter); ok {
+		br.EndFlush()
+	}
+}
+
+// Counter reports a counter value.
+func (r *reporter) Counter(name string, v int64, tags [][2]string) {
+	if name, tags, ok := r.apply(name, tags); ok {
+		r.r.Counter(name, v, tags)
+	}
+}
+
+// SampledCounter reports a sampled counter value with its sample rate if
+// the reporter implements [statter.SampledReporter], and scaled by the
+// sample rate otherwise.
+func (r *reporter) SampledCounter(name string, v int64, rate float64, tags [][2]string) {
+	name, tags, ok := r.apply(name, tags)
+	if !ok {
+		return
+	}
+	if sr, ok := r.r.(statter.SampledReporter); ok {
+		sr.SampledCounter(name, v, rate, tags)
+		return
+	}
+	r.r.Counter(name, int64(math.Round(float64(v)/rate)), tags)
+}
+
+// RemoveCounter removes the counter if the reporter implements
+// [statter.RemovableReporter].
+func (r *reporter) RemoveCounter(name string, tags [][2]string) {
+	rr, ok := r.r.(statter.RemovableReporter)
+	if !ok {
+		return
+	}
+	if name, tags, ok = r.apply(name, tags); ok {
+		rr.RemoveCounter(name, tags)
+	}
+}
+
+// AggregateCounter reports a counter aggregated from metric, applying the
+// rules to the name of the metric.
+func (r *reporter) AggregateCounter(metric, name string, v int64, tags [][2]string) {
+	m, tags, ok := r.apply(metric, tags)
+	if !ok {
+		return
+	}
+	name = m + strings.TrimPrefix(name, metric)
+	if ar, ok := r.r.(statter.AggregateReporter); ok {
+		ar.AggregateCounter(m, name, v, tags)
+		return
+	}
+	r.r.Counter(name, v, tags)
+}
+
+// AggregateGauge reports a gauge aggregated from metric, applying the
+// rules to the name of the metric.
+func (r *reporter) AggregateGauge(metric, name string, v float64, tags [][2]string) {
+	m, tags, ok := r.apply(metric, tags)
+	if !ok {
+		return
+	}
+	name = m + strings.TrimPrefix(name, metric)
+	if ar, ok := r.r.(statter.AggregateReporter); ok {
+		ar.AggregateGauge(m, name, v, tags)
+		return
+	}
+	r.r.Gauge(name, v, tags)
+}
+
+// Gauge reports a gauge value.
+func (r *reporter) Gauge(name string, v float64, tags [][2]string) {
+	if name, tags, ok := r.apply(name, tags); ok {
+		r.r.Gauge(name, v, tags)
+	}
+}
+
+// RemoveGauge removes the gauge if the reporter implements
+// [statter.RemovableReporter].
+func (r *reporter) RemoveGauge(name string, tags [][2]string) {
+	rr, ok := r.r.(statter.RemovableReporter)
+	if !ok {
+		return
+	}
+	if name, tags, ok = r.apply(name, tags); ok {
+		rr.RemoveGauge(name, tags)
+	}
+}
+
+// Histogram returns a function reporting histogram values if the reporter
+// implements [statter.HistogramReporter], and nil otherwise.
+func (r *reporter) Histogram(name string, tags [][2]string) func(v float64) {
+	name, tags, ok := r.apply(name, tags)
+	if !ok {
+		return func(float64) {}
+	}
+	if hr, ok := r.r.(statter.HistogramReporter); ok {
+		return hr.Histogram(name, tags)
+	}
+	return nil
+}
+
+// HistogramWithExemplar returns a function reporting histogram values with
+// exemplars if the reporter implements [statter.ExemplarReporter], and nil
+// otherwise.
+func (r *reporter) HistogramWithExemplar(name string, tags [][2]string) func(v float64, exemplar [][2]string) {
+	name, tags, ok := r.apply(name, tags)
+	if !ok {
+		return nil
+	}
+	if er, ok := r.r.(statter.ExemplarReporter); ok {
+		return er.HistogramWithExemplar(name, tags)
+	}
+	return nil
+}
+
+// RemoveHistogram removes the histogram if the reporter implements
+// [statter.RemovableHistogramReporter].
+func (r *reporter) RemoveHistogram(name string, tags [][2]string) {
+	rhr, ok := r.r.(statter.RemovableHistogramReporter)
+	if !ok {
+		return
+	}
+	if name, tags, ok = r.apply(name, tags); ok {
+		rhr.RemoveHistogram(name, tags)
+	}
+}
+
+// Timing returns a function reporting timing values if the reporter
+// implements [statter.TimingReporter], and nil otherwise.
+func (r *reporter) Timing(name string, tags [][2]string) func(v time.Duration) {
+	name, tags, ok := r.apply(name, tags)
+	if !ok {
+		return func(time.Duration) {}
+	}
+	if tr, ok := r.r.(statter.TimingReporter); ok {
+		return tr.Timing(name, tags)
+	}
+	return nil
+}
+
+// TimingWithExemplar returns a function reporting timing values with
+// exemplars if the reporter implements [statter.ExemplarReporter], and nil
+// otherwise.
+func (r *reporter) TimingWithExemplar(name string, tags [][2]string) func(v time.Duration, exemplar [][2]string) {
+	name, tags, ok := r.apply(name, tags)
+	if !ok {
+		return nil
+	}
+	if er, ok := r.r.(statter.ExemplarReporter); ok {
+		return er.TimingWithExemplar(name, tags)
+	}
+	return nil
+}
+
+// RemoveTiming removes the timing if the reporter implements
+// [statter.RemovableTimingReporter].
+func (r *reporter) RemoveTiming(name string, tags [][2]string) {
+	rtr, ok := r.r.(statter.RemovableTimingReporter)
+	if !ok {
+		return
+	}
+	if name, tags, ok = r.apply(name, tags); ok {
+		rtr.RemoveTiming(name, tags)
+	}
+}
+
+// Set returns a function adding values to a set if the reporter implements
+// [statter.SetReporter], and nil otherwise.
+func (r *reporter) Set(name string, tags [][2]string) func(v string) {
+	name, tags, ok := r.apply(name, tags)
+	if !ok {
+		return func(string) {}
+	}
+	if sr, ok := r.r.(statter.SetReporter); ok {
+		return sr.Set(name, tags)
+	}
+	return nil
+}
+
+// RemoveSet removes the set if the reporter implements
+// [statter.RemovableSetReporter].
+func (r *reporter) RemoveSet(name string, tags [][2]string) {
+	rsr, ok := r.r.(statter.RemovableSetReporter)
+	if !ok {
+		return
+	}
+	if name, tags, ok = r.apply(name, tags); ok {
+		rsr.RemoveSet(name, tags)
+	}
+}
+
+// Distribution returns a function reporting distribution values if the
+// reporter implements [statter.DistributionReporter], and nil otherwise.
+func (r *reporter) Distribution(name string, tags [][2]string) func(v float64) {
+	name, tags, ok := r.apply(name, tags)
+	if !ok {
+		return func(float64) {}
+	}
+	if dr, ok := r.r.(statter.DistributionReporter); ok {
+		return dr.Distribution(name, tags)
+	}
+	return nil
+}
+
+// RemoveDistribution removes the distribution if the reporter implements
+// [statter.RemovableDistributionReporter].
+func (r *reporter) RemoveDistribution(name string, tags [][2]string) {
+	rdr, ok := r.r.(statter.RemovableDistributionReporter)
+	if !ok {
+		return
+	}
+	if name, tags, ok = r.apply(name, tags); ok {
+		rdr.RemoveDistribution(name, tags)
+	}
+}
+
+// Close closes the reporter if it implements [io.Closer].
+func (r *reporter) Close() error {
+	if c, ok := r.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// gaugeFuncReporter is a reporter wrapping a [statter.GaugeFuncReporter].
+//
+// It is a distinct type as the statter only reports gauge functions as
+// gauges to reporters not implementing [statter.GaugeFuncReporter].
+type gaugeFuncReporter struct {
+	*reporter
+
+	gfr statter.GaugeFuncReporter
+}
+
+// GaugeFunc registers the gauge function.
+func (r *gaugeFuncReporter) GaugeFunc(name string, fn func() float64, tags [][2]string) {
+	if name, tags, ok := r.apply(name, tags); ok {
+		r.gfr.GaugeFunc(name, fn, tags)
+	}
+}
+
+// RemoveGaugeFunc removes the gauge function.
+func (r *gaugeFuncReporter) RemoveGaugeFunc(name string, tags [][2]string) {
+	if name, tags, ok := r.apply(name, tags); ok {
+		r.gfr.RemoveGaugeFunc(name, tags)
+	}
+}
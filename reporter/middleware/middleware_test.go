@@ -0,0 +1,283 @@
+package middleware_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/reporter/middleware"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	r := middleware.New(statstest.NewReporter())
+
+	assert.Implements(t, (*statter.Reporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableReporter)(nil), r)
+	assert.Implements(t, (*statter.HistogramReporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableHistogramReporter)(nil), r)
+	assert.Implements(t, (*statter.TimingReporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableTimingReporter)(nil), r)
+	assert.Implements(t, (*statter.SetReporter)(nil), r)
+	assert.Implements(t, (*statter.DistributionReporter)(nil), r)
+	assert.Implements(t, (*statter.ExemplarReporter)(nil), r)
+	assert.Implements(t, (*statter.SampledReporter)(nil), r)
+	assert.Implements(t, (*statter.AggregateReporter)(nil), r)
+	assert.Implements(t, (*statter.ErrorReporter)(nil), r)
+	assert.Implements(t, (*statter.DescribingReporter)(nil), r)
+	assert.Implements(t, (*statter.FlushReporter)(nil), r)
+	assert.Implements(t, (*statter.BatchReporter)(nil), r)
+	assert.Implements(t, (*statter.WrappingReporter)(nil), r)
+	assert.NotImplements(t, (*statter.GaugeFuncReporter)(nil), r)
+}
+
+func TestNew_PreservesGaugeFuncReporter(t *testing.T) {
+	r := middleware.New(&gaugeFuncReporter{})
+
+	assert.Implements(t, (*statter.GaugeFuncReporter)(nil), r)
+}
+
+func TestReporter_AppliesRules(t *testing.T) {
+	r := statstest.NewReporter()
+	stats := statter.New(middleware.New(r,
+		middleware.Deny("noisy.*"),
+		middleware.Rename("legacy", "modern"),
+		middleware.DropTags("user"),
+		middleware.Prefix("app."),
+	), 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	stats.Counter("legacy", tags.Str("user", "1"), tags.Str("foo", "bar")).Inc(2)
+	stats.Counter("noisy.counter").Inc(1)
+	stats.Histogram("noisy.histo").Observe(1)
+	stats.Timing("legacy").Observe(time.Second)
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	r.AssertCounter(t, "app.modern", []statter.Tag{tags.Str("foo", "bar")}, 2)
+	r.AssertNoCounter(t, "noisy.counter", nil)
+	r.AssertNoCounter(t, "app.noisy.counter", nil)
+	assert.Empty(t, r.HistogramValues("app.noisy.histo", nil))
+	r.AssertTiming(t, "app.modern", nil, time.Second)
+}
+
+func TestReporter_AggregatesUnhandledHistograms(t *testing.T) {
+	r := &simpleReporter{counters: map[string]int64{}}
+	stats := statter.New(middleware.New(r, middleware.Prefix("app.")), 0)
+
+	stats.Histogram("test").Observe(1)
+	stats.Histogram("test").Observe(2)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), r.counters["app.test_count"])
+}
+
+func TestReporter_RulesMatchAggregatedMetricName(t *testing.T) {
+	r := &simpleReporter{counters: map[string]int64{}}
+	stats := statter.New(middleware.New(r,
+		middleware.Deny("latency"),
+		middleware.Rename("size", "bytes"),
+	), 0, statter.WithPercentiles([]float64{50}))
+
+	stats.Histogram("latency").Observe(1)
+	stats.Timing("latency").Observe(time.Second)
+	stats.Histogram("size").Observe(2)
+	stats.Distribution("latency").Observe(3)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"bytes_count": 1}, r.counters)
+	assert.Equal(t, map[string]float64{
+		"bytes_sum":    2,
+		"bytes_mean":   2,
+		"bytes_stddev": 0,
+		"bytes_min":    2,
+		"bytes_max":    2,
+		"bytes_50p":    2,
+	}, r.gauges)
+}
+
+func TestReporter_SplitsInMultiReporter(t *testing.T) {
+	plain := &simpleReporter{counters: map[string]int64{}}
+	native := statstest.NewReporter()
+	stats := statter.New(statter.MultiReporter(middleware.New(plain, middleware.Prefix("app.")), native), 0)
+
+	stats.Histogram("test").Observe(1)
+	stats.Histogram("test").Observe(2)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), plain.counters["app.test_count"])
+	assert.Equal(t, []float64{1, 2}, native.HistogramValues("test", nil))
+}
+
+func TestReporter_WrapsMultiReporter(t *testing.T) {
+	plain := &simpleReporter{counters: map[string]int64{}}
+	native := statstest.NewReporter()
+	stats := statter.New(middleware.New(statter.MultiReporter(plain, native), middleware.Prefix("app.")), 0)
+
+	stats.Histogram("test").Observe(1)
+	stats.Histogram("test").Observe(2)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(2), plain.counters["app.test_count"])
+	assert.Equal(t, []float64{1, 2}, native.HistogramValues("app.test", nil))
+}
+
+func TestReporter_Remove(t *testing.T) {
+	r := statstest.NewReporter()
+	stats := statter.New(middleware.New(r, middleware.Rename("legacy", "modern")), 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	c := stats.Counter("legacy")
+	c.Inc(1)
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	c.Delete()
+
+	r.AssertNoCounter(t, "modern", nil)
+}
+
+func TestReporter_SampledCounterScales(t *testing.T) {
+	r := statstest.NewReporter()
+	mw := middleware.New(r)
+
+	mw.(statter.SampledReporter).SampledCounter("test", 2, 0.5, nil)
+
+	r.AssertCounter(t, "test", nil, 4)
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     middleware.Rule
+		in       [][2]string
+		wantName string
+		wantTags [][2]string
+		wantOK   bool
+	}{
+		{
+			name:     "allow matches",
+			rule:     middleware.Allow("foo.*", "baz"),
+			wantName: "foo.bar",
+			wantOK:   true,
+		},
+		{
+			name:   "allow does not match",
+			rule:   middleware.Allow("bar.*", "[bad"),
+			wantOK: false,
+		},
+		{
+			name:   "deny matches",
+			rule:   middleware.Deny("*.bar"),
+			wantOK: false,
+		},
+		{
+			name:     "allow regexp",
+			rule:     middleware.AllowRegexp(regexp.MustCompile(`^foo\.`)),
+			wantName: "foo.bar",
+			wantOK:   true,
+		},
+		{
+			name:   "deny regexp",
+			rule:   middleware.DenyRegexp(regexp.MustCompile(`bar$`)),
+			wantOK: false,
+		},
+		{
+			name:     "rename",
+			rule:     middleware.Rename("foo.bar", "baz"),
+			wantName: "baz",
+			wantOK:   true,
+		},
+		{
+			name:     "rename regexp",
+			rule:     middleware.RenameRegexp(regexp.MustCompile(`^foo\.`), "qux_"),
+			wantName: "qux_bar",
+			wantOK:   true,
+		},
+		{
+			name:     "prefix",
+			rule:     middleware.Prefix("app."),
+			wantName: "app.foo.bar",
+			wantOK:   true,
+		},
+		{
+			name:     "drop tags",
+			rule:     middleware.DropTags("a", "c"),
+			in:       [][2]string{{"a", "1"}, {"b", "2"}, {"c", "3"}},
+			wantName: "foo.bar",
+			wantTags: [][2]string{{"b", "2"}},
+			wantOK:   true,
+		},
+		{
+			name:     "rename tag",
+			rule:     middleware.RenameTag("a", "b"),
+			in:       [][2]string{{"a", "1"}, {"b", "2"}, {"c", "3"}},
+			wantName: "foo.bar",
+			wantTags: [][2]string{{"c", "3"}, {"b", "1"}},
+			wantOK:   true,
+		},
+		{
+			name:     "add tags",
+			rule:     middleware.AddTags(tags.Str("a", "new"), tags.Str("d", "4")),
+			in:       [][2]string{{"a", "1"}, {"b", "2"}},
+			wantName: "foo.bar",
+			wantTags: [][2]string{{"a", "new"}, {"b", "2"}, {"d", "4"}},
+			wantOK:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := append([][2]string(nil), test.in...)
+
+			name, gotTags, ok := test.rule("foo.bar", in)
+
+			assert.Equal(t, test.wantOK, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, test.wantName, name)
+			if test.wantTags != nil {
+				assert.Equal(t, test.wantTags, gotTags)
+			}
+			assert.Equal(t, test.in, in, "tags modified in place")
+		})
+	}
+}
+
+type simpleReporter struct {
+	counters map[string]int64
+	gauges   map[string]float64
+}
+
+func (r *simpleReporter) Counter(name string, v int64, _ [][2]string) {
+	r.counters[name] += v
+}
+
+func (r *simpleReporter) Gauge(name string, v float64, _ [][2]string) {
+	if r.gauges == nil {
+		r.gauges = map[string]float64{}
+	}
+	r.gauges[name] = v
+}
+
+type gaugeFuncReporter struct {
+	simpleReporter
+}
+
+func (r *gaugeFuncReporter) GaugeFunc(string, func() float64, [][2]string) {}
+
+func (r *gaugeFuncReporter) RemoveGaugeFunc(string, [][2]string) {}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"path"
+	"regexp"
+	"slices"
+
+	"github.com/hamba/statter/v2"
+)
+
+// Allow returns a rule keeping only the metrics with a name matching one of
+// the glob patterns, as in [path.Match]. Malformed patterns match nothing.
+func Allow(patterns ...string) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		return name, tags, matchAny(patterns, name)
+	}
+}
+
+// Deny returns a rule dropping the metrics with a name matching one of the
+// glob patterns, as in [path.Match]. Malformed patterns match nothing.
+func Deny(patterns ...string) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		return name, tags, !matchAny(patterns, name)
+	}
+}
+
+// AllowRegexp returns a rule keeping only the metrics with a name
+// matching re.
+func AllowRegexp(re *regexp.Regexp) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		return name, tags, re.MatchString(name)
+	}
+}
+
+// DenyRegexp returns a rule dropping the metrics with a name matching re.
+func DenyRegexp(re *regexp.Regexp) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		return name, tags, !re.MatchString(name)
+	}
+}
+
+// Rename returns a rule renaming the metrics named from to to.
+func Rename(from, to string) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		if name == from {
+			name = to
+		}
+		return name, tags, true
+	}
+}
+
+// RenameRegexp returns a rule replacing the matches of re in metric names
+// with repl, as in [regexp.Regexp.ReplaceAllString].
+func RenameRegexp(re *regexp.Regexp, repl string) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		return re.ReplaceAllString(name, repl), tags, true
+	}
+}
+
+// Prefix returns a rule prepending prefix, including any separator,
+// to metric names.
+func Prefix(prefix string) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		return prefix + name, tags, true
+	}
+}
+
+// DropTags returns a rule removing the tags with the given keys.
+func DropTags(keys ...string) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		if !slices.ContainsFunc(tags, func(t [2]string) bool { return slices.Contains(keys, t[0]) }) {
+			return name, tags, true
+		}
+
+		newTags := make([][2]string, 0, len(tags))
+		for _, t := range tags {
+			if slices.Contains(keys, t[0]) {
+				continue
+			}
+			newTags = append(newTags, t)
+		}
+		return name, newTags, true
+	}
+}
+
+// RenameTag returns a rule renaming the tag key from to to. An existing
+// tag with key to is replaced.
+func RenameTag(from, to string) Rule {
+	return func(name string, tags [][2]string) (string, [][2]string, bool) {
+		i := tagIndex(tags, from)
+		if i < 0 {
+			return name, tags, true
+		}
+
+		val := tags[i][1]
+		newTags := make([][2]string, 0, len(tags))
+		for _, t := range tags {
+			if t[0] == from || t[0] == to {
+				continue
+			}
+			newTags = append(newTags, t)
+		}
+		return name, append(newTags, [2]string{to, val}), true
+	}
+}
+
+// AddTags returns a rule adding the tags to every metric. A tag replaces
+// an existing tag with the same key.
+func AddTags(tags ...statter.Tag) Rule {
+	return func(name string, t [][2]string) (string, [][2]string, bool) {
+		newTags := make([][2]string, len(t), len(t)+len(tags))
+		copy(newTags, t)
+		for _, tag := range tags {
+			if i := tagIndex(newTags, tag[0]); i >= 0 {
+				newTags[i][1] = tag[1]
+				continue
+			}
+			newTags = append(newTags, tag)
+		}
+		return name, newTags, true
+	}
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func tagIndex(tags [][2]string, key string) int {
+	for i, t := range tags {
+		if t[0] == key {
+			return i
+		}
+	}
+	return -1
+}
@@ -8,11 +8,13 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go4org/hashtriemap"
 	"github.com/hamba/statter/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/model"
 )
 
 // Option represents a prometheus option function.
@@ -43,14 +45,17 @@ type Prometheus struct {
 
 	defBuckets []float64
 	buckets    hashtriemap.HashTrieMap[string, []float64]
+	help       hashtriemap.HashTrieMap[string, string]
 
 	reg        *prometheus.Registry
 	counters   hashtriemap.HashTrieMap[string, *prometheus.CounterVec]
 	gauges     hashtriemap.HashTrieMap[string, *prometheus.GaugeVec]
 	histograms hashtriemap.HashTrieMap[string, *prometheus.HistogramVec]
 	timings    hashtriemap.HashTrieMap[string, *prometheus.HistogramVec]
+	gaugeFuncs hashtriemap.HashTrieMap[string, prometheus.GaugeFunc]
 
 	errLog func(string)
+	errFn  func(name string, tags [][2]string, err error)
 }
 
 // New returns a new prometheus reporter.
@@ -73,8 +78,30 @@ func New(namespace string, opts ...Option) *Prometheus {
 }
 
 // Handler returns the prometheus HTTP handler for scraping metrics.
+// OpenMetrics is served when requested by the scraper, exposing exemplars.
 func (p *Prometheus) Handler() http.Handler {
-	return promhttp.HandlerFor(p.reg, promhttp.HandlerOpts{})
+	return promhttp.HandlerFor(p.reg, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// OnError sets the function called when registering a metric fails.
+// Once set, registration errors are no longer sent to the error log.
+func (p *Prometheus) OnError(fn func(name string, tags [][2]string, err error)) {
+	p.errFn = fn
+}
+
+// Describe sets the help text of the metric with the given name. As
+// Prometheus has no unit metadata, the unit is appended to the help text.
+//
+// Describe only affects metrics that have not yet been reported.
+func (p *Prometheus) Describe(name string, meta statter.Metadata) {
+	help := meta.Help
+	if help == "" {
+		help = name
+	}
+	if meta.Unit != "" {
+		help += " (" + meta.Unit + ")"
+	}
+	p.help.Store(name, help)
 }
 
 // Counter reports a counter value.
@@ -88,16 +115,14 @@ func (p *Prometheus) Counter(name string, v int64, tags [][2]string) {
 			prometheus.CounterOpts{
 				Namespace: p.namespace,
 				Name:      p.fqn.Format(name),
-				Help:      name,
+				Help:      p.getHelp(name),
 			},
 			lblNames,
 		)
 
 		m, ok = p.counters.LoadOrStore(key, counter)
 		if !ok {
-			if err := p.reg.Register(m); err != nil {
-				p.errLog(fmt.Sprintf("Could not to register Prometheus counter %q: %v\n", name, err))
-			}
+			p.register(m, "counter", name, tags)
 		}
 	}
 
@@ -127,16 +152,14 @@ func (p *Prometheus) Gauge(name string, v float64, tags [][2]string) {
 			prometheus.GaugeOpts{
 				Namespace: p.namespace,
 				Name:      p.fqn.Format(name),
-				Help:      name,
+				Help:      p.getHelp(name),
 			},
 			lblNames,
 		)
 
 		m, ok = p.gauges.LoadOrStore(key, gauge)
 		if !ok {
-			if err := p.reg.Register(m); err != nil {
-				p.errLog(fmt.Sprintf("Could not to register Prometheus gauge %q: %v\n", name, err))
-			}
+			p.register(m, "gauge", name, tags)
 		}
 	}
 
@@ -155,8 +178,55 @@ func (p *Prometheus) RemoveGauge(name string, tags [][2]string) {
 	m.Delete(lbls)
 }
 
+// GaugeFunc registers a gauge whose value is returned by fn
+// when metrics are scraped.
+func (p *Prometheus) GaugeFunc(name string, fn func() float64, tags [][2]string) {
+	_, lbls := formatTags(tags, p.fqn)
+	key := createSeriesKey(name, tags)
+
+	gauge := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace:   p.namespace,
+			Name:        p.fqn.Format(name),
+			Help:        p.getHelp(name),
+			ConstLabels: lbls,
+		},
+		fn,
+	)
+
+	if _, ok := p.gaugeFuncs.LoadOrStore(key, gauge); !ok {
+		p.register(gauge, "gauge func", name, tags)
+	}
+}
+
+// RemoveGaugeFunc removes the gauge function.
+func (p *Prometheus) RemoveGaugeFunc(name string, tags [][2]string) {
+	key := createSeriesKey(name, tags)
+
+	m, ok := p.gaugeFuncs.LoadAndDelete(key)
+	if !ok {
+		return
+	}
+	p.reg.Unregister(m)
+}
+
 // Histogram reports a histogram value.
 func (p *Prometheus) Histogram(name string, tags [][2]string) func(v float64) {
+	o := p.histogram(name, tags)
+	return func(v float64) {
+		o.Observe(v)
+	}
+}
+
+// HistogramWithExemplar reports a histogram value with an exemplar.
+func (p *Prometheus) HistogramWithExemplar(name string, tags [][2]string) func(v float64, exemplar [][2]string) {
+	o := p.histogram(name, tags)
+	return func(v float64, exemplar [][2]string) {
+		p.observeWithExemplar(o, name, tags, v, exemplar)
+	}
+}
+
+func (p *Prometheus) histogram(name string, tags [][2]string) prometheus.Observer {
 	lblNames, lbls := formatTags(tags, p.fqn)
 	key := createKey(name, lblNames)
 
@@ -168,23 +238,18 @@ func (p *Prometheus) Histogram(name string, tags [][2]string) func(v float64) {
 				Namespace: p.namespace,
 				Name:      p.fqn.Format(name),
 				Buckets:   buckets,
-				Help:      name,
+				Help:      p.getHelp(name),
 			},
 			lblNames,
 		)
 
 		m, ok = p.histograms.LoadOrStore(key, histo)
 		if !ok {
-			if err := p.reg.Register(m); err != nil {
-				p.errLog(fmt.Sprintf("Could not to register Prometheus histogram %q: %v\n", name, err))
-			}
+			p.register(m, "histogram", name, tags)
 		}
 	}
 
-	o := m.With(lbls)
-	return func(v float64) {
-		o.Observe(v)
-	}
+	return m.With(lbls)
 }
 
 // RemoveHistogram removes the histogram.
@@ -199,8 +264,34 @@ func (p *Prometheus) RemoveHistogram(name string, tags [][2]string) {
 	m.Delete(lbls)
 }
 
+// Distribution reports a distribution value as a histogram, which can be
+// aggregated across instances by Prometheus.
+func (p *Prometheus) Distribution(name string, tags [][2]string) func(v float64) {
+	return p.Histogram(name, tags)
+}
+
+// RemoveDistribution removes the distribution.
+func (p *Prometheus) RemoveDistribution(name string, tags [][2]string) {
+	p.RemoveHistogram(name, tags)
+}
+
 // Timing reports a timing value as a histogram in seconds.
 func (p *Prometheus) Timing(name string, tags [][2]string) func(v time.Duration) {
+	o := p.timing(name, tags)
+	return func(v time.Duration) {
+		o.Observe(v.Seconds())
+	}
+}
+
+// TimingWithExemplar reports a timing value with an exemplar as a histogram in seconds.
+func (p *Prometheus) TimingWithExemplar(name string, tags [][2]string) func(v time.Duration, exemplar [][2]string) {
+	o := p.timing(name, tags)
+	return func(v time.Duration, exemplar [][2]string) {
+		p.observeWithExemplar(o, name, tags, v.Seconds(), exemplar)
+	}
+}
+
+func (p *Prometheus) timing(name string, tags [][2]string) prometheus.Observer {
 	lblNames, lbls := formatTags(tags, p.fqn)
 	key := createKey(name, lblNames)
 
@@ -212,23 +303,18 @@ func (p *Prometheus) Timing(name string, tags [][2]string) func(v time.Duration)
 				Namespace: p.namespace,
 				Name:      p.fqn.Format(name),
 				Buckets:   buckets,
-				Help:      name,
+				Help:      p.getHelp(name),
 			},
 			lblNames,
 		)
 
 		m, ok = p.timings.LoadOrStore(key, timing)
 		if !ok {
-			if err := p.reg.Register(m); err != nil {
-				p.errLog(fmt.Sprintf("Could not to register Prometheus histogram %q: %v\n", name, err))
-			}
+			p.register(m, "histogram", name, tags)
 		}
 	}
 
-	o := m.With(lbls)
-	return func(v time.Duration) {
-		o.Observe(v.Seconds())
-	}
+	return m.With(lbls)
 }
 
 // RemoveTiming removes the timing.
@@ -243,6 +329,71 @@ func (p *Prometheus) RemoveTiming(name string, tags [][2]string) {
 	m.Delete(lbls)
 }
 
+// observeWithExemplar observes the value with the exemplar. Invalid
+// exemplars are reported as errors and the value is observed without it.
+func (p *Prometheus) observeWithExemplar(o prometheus.Observer, name string, tags [][2]string, v float64, exemplar [][2]string) {
+	eo, ok := o.(prometheus.ExemplarObserver)
+	if !ok || len(exemplar) == 0 {
+		o.Observe(v)
+		return
+	}
+
+	lbls := make(prometheus.Labels, len(exemplar))
+	for _, l := range exemplar {
+		lbls[p.fqn.Format(l[0])] = l[1]
+	}
+	if err := validateExemplar(lbls); err != nil {
+		if p.errFn != nil {
+			p.errFn(name, tags, fmt.Errorf("could not add prometheus exemplar: %w", err))
+		} else {
+			p.errLog(fmt.Sprintf("Could not add Prometheus exemplar %q: %v\n", name, err))
+		}
+		o.Observe(v)
+		return
+	}
+	eo.ObserveWithExemplar(v, lbls)
+}
+
+// validateExemplar validates the exemplar labels, as an invalid
+// exemplar causes the observation to panic.
+func validateExemplar(lbls prometheus.Labels) error {
+	var runes int
+	for name, val := range lbls {
+		if !model.LegacyValidation.IsValidLabelName(name) {
+			return fmt.Errorf("exemplar label name %q is invalid", name)
+		}
+		if !utf8.ValidString(val) {
+			return fmt.Errorf("exemplar label value %q is not valid UTF-8", val)
+		}
+		runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(val)
+	}
+	if runes > prometheus.ExemplarMaxRunes {
+		return fmt.Errorf("exemplar labels have %d runes, exceeding the limit of %d", runes, prometheus.ExemplarMaxRunes)
+	}
+	return nil
+}
+
+func (p *Prometheus) register(c prometheus.Collector, typ, name string, tags [][2]string) {
+	err := p.reg.Register(c)
+	if err == nil {
+		return
+	}
+
+	if p.errFn != nil {
+		p.errFn(name, tags, fmt.Errorf("could not register prometheus %s: %w", typ, err))
+		return
+	}
+	p.errLog(fmt.Sprintf("Could not to register Prometheus %s %q: %v\n", typ, name, err))
+}
+
+func (p *Prometheus) getHelp(name string) string {
+	h, ok := p.help.Load(name)
+	if !ok {
+		return name
+	}
+	return h
+}
+
 func (p *Prometheus) getBuckets(name string) []float64 {
 	b, ok := p.buckets.Load(name)
 	if !ok {
@@ -349,6 +500,19 @@ func createKey(name string, lblNames []string) string {
 	return name + strings.Join(lblNames, ":")
 }
 
+// createSeriesKey creates a unique key for a single series.
+func createSeriesKey(name string, tags [][2]string) string {
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, tag := range tags {
+		sb.WriteByte(':')
+		sb.WriteString(tag[0])
+		sb.WriteByte('=')
+		sb.WriteString(tag[1])
+	}
+	return sb.String()
+}
+
 // formatTags creates a prometheus Label map from tags.
 func formatTags(tags [][2]string, fqn *fqn) ([]string, prometheus.Labels) {
 	names := make([]string, 0, len(tags))
@@ -3,6 +3,7 @@ package prometheus_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +31,12 @@ func TestNew(t *testing.T) {
 	assert.Implements(t, (*statter.RemovableHistogramReporter)(nil), p)
 	assert.Implements(t, (*statter.TimingReporter)(nil), p)
 	assert.Implements(t, (*statter.RemovableTimingReporter)(nil), p)
+	assert.Implements(t, (*statter.ErrorReporter)(nil), p)
+	assert.Implements(t, (*statter.DescribingReporter)(nil), p)
+	assert.Implements(t, (*statter.GaugeFuncReporter)(nil), p)
+	assert.Implements(t, (*statter.DistributionReporter)(nil), p)
+	assert.Implements(t, (*statter.RemovableDistributionReporter)(nil), p)
+	assert.Implements(t, (*statter.ExemplarReporter)(nil), p)
 }
 
 func TestPrometheus_Counter(t *testing.T) {
@@ -88,6 +95,36 @@ func TestPrometheus_RemoveGauge(t *testing.T) {
 	assert.NotContains(t, rr.Body.String(), "test_test_test{foo=\"bar\"} 2.1")
 }
 
+func TestPrometheus_GaugeFunc(t *testing.T) {
+	p := prometheus.New("test.test")
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.GaugeFunc("test", func() float64 { return 2.1 }, [][2]string{{"foo", "bar"}})
+	p.GaugeFunc("test", func() float64 { return 3.1 }, [][2]string{{"foo", "baz"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Body.String(), "test_test_test{foo=\"bar\"} 2.1")
+	assert.Contains(t, rr.Body.String(), "test_test_test{foo=\"baz\"} 3.1")
+}
+
+func TestPrometheus_RemoveGaugeFunc(t *testing.T) {
+	p := prometheus.New("test.test")
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.GaugeFunc("test", func() float64 { return 2.1 }, [][2]string{{"foo", "bar"}})
+
+	p.RemoveGaugeFunc("test", [][2]string{{"foo", "bar"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.NotContains(t, rr.Body.String(), "test_test_test{foo=\"bar\"} 2.1")
+}
+
 func TestPrometheus_Histogram(t *testing.T) {
 	p := prometheus.New("test.test", prometheus.WithBuckets([]float64{0.1, 1.0}))
 	t.Cleanup(func() { _ = p.Close() })
@@ -138,6 +175,55 @@ func TestPrometheus_Timing(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "test_test_test_count{foo=\"bar\"} 1")
 }
 
+func TestPrometheus_HistogramWithExemplar(t *testing.T) {
+	p := prometheus.New("test.test", prometheus.WithBuckets([]float64{0.1, 1.0}))
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.HistogramWithExemplar("test", [][2]string{{"foo", "bar"}})(0.0123, [][2]string{{"trace_id", "abc"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Body.String(), "test_test_test_bucket{foo=\"bar\",le=\"0.1\"} 1 # {trace_id=\"abc\"} 0.0123")
+	assert.Contains(t, rr.Body.String(), "test_test_test_count{foo=\"bar\"} 1")
+}
+
+func TestPrometheus_HistogramWithExemplarHandlesInvalidExemplar(t *testing.T) {
+	p := prometheus.New("test.test", prometheus.WithBuckets([]float64{0.1, 1.0}))
+	t.Cleanup(func() { _ = p.Close() })
+
+	var gotErr error
+	p.OnError(func(_ string, _ [][2]string, err error) {
+		gotErr = err
+	})
+
+	p.HistogramWithExemplar("test", [][2]string{{"foo", "bar"}})(0.0123, [][2]string{{"trace_id", strings.Repeat("a", 200)}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.Error(t, gotErr)
+	assert.Contains(t, rr.Body.String(), "test_test_test_bucket{foo=\"bar\",le=\"0.1\"} 1\n")
+}
+
+func TestPrometheus_TimingWithExemplar(t *testing.T) {
+	p := prometheus.New("test.test", prometheus.WithBuckets([]float64{0.1, 1.0}))
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.TimingWithExemplar("test", [][2]string{{"foo", "bar"}})(1234500*time.Nanosecond, [][2]string{{"trace_id", "abc"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Body.String(), "test_test_test_bucket{foo=\"bar\",le=\"0.1\"} 1 # {trace_id=\"abc\"} 0.0012345")
+}
+
 func TestPrometheus_RemoveTiming(t *testing.T) {
 	p := prometheus.New("test.test", prometheus.WithBuckets([]float64{0.1, 1.0}))
 	t.Cleanup(func() { _ = p.Close() })
@@ -182,6 +268,43 @@ func TestPrometheus_NoPrefixNoTags(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "test 2")
 }
 
+func TestPrometheus_Describe(t *testing.T) {
+	p := prometheus.New("test.test")
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.Describe("test", statter.Metadata{Help: "my counter", Unit: "requests"})
+	p.Counter("test", 2, [][2]string{{"foo", "bar"}})
+	p.Gauge("other", 2.1, [][2]string{{"foo", "bar"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Body.String(), "# HELP test_test_test my counter (requests)")
+	assert.Contains(t, rr.Body.String(), "# HELP test_test_other other")
+}
+
+func TestPrometheus_OnError(t *testing.T) {
+	var logged bool
+	p := prometheus.New("test.test", prometheus.WithErrorLog(func(string) { logged = true }))
+	t.Cleanup(func() { _ = p.Close() })
+
+	var (
+		gotName string
+		gotErr  error
+	)
+	p.OnError(func(name string, _ [][2]string, err error) {
+		gotName, gotErr = name, err
+	})
+
+	p.Counter("test", 2, [][2]string{{"foo", "bar"}})
+	p.Gauge("test", 2.1, [][2]string{{"foo", "bar"}})
+
+	assert.Equal(t, "test", gotName)
+	assert.Error(t, gotErr)
+	assert.False(t, logged)
+}
+
 func TestPrometheus_Close(t *testing.T) {
 	p := prometheus.New("test.test")
 	t.Cleanup(func() { _ = p.Close() })
@@ -18,3 +18,14 @@ func ExampleNew() {
 
 	statter.New(reporter, 10*time.Second)
 }
+
+func ExampleNewDogStatsD() {
+	reporter, err := statsd.NewDogStatsD("127.0.0.1:8125", "my-prefix")
+	if err != nil {
+		panic(err)
+	}
+
+	stats := statter.New(reporter, 10*time.Second)
+
+	stats.Distribution("latency").Observe(1.25)
+}
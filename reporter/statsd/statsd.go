@@ -3,6 +3,7 @@ package statsd
 
 import (
 	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -45,10 +46,16 @@ type Statsd struct {
 	cfg    config
 	client statsd.Statter
 	es     statsd.ExtendedStatSender
+
+	errFn func(name string, tags [][2]string, err error)
 }
 
 // New returns a statsd reporter.
 func New(addr, prefix string, opts ...Option) (*Statsd, error) {
+	return newStatsd(addr, prefix, statsd.InfixComma, opts)
+}
+
+func newStatsd(addr, prefix string, tagFormat statsd.TagFormat, opts []Option) (*Statsd, error) {
 	cfg := defaultConfig()
 	for _, o := range opts {
 		o(&cfg)
@@ -60,7 +67,7 @@ func New(addr, prefix string, opts ...Option) (*Statsd, error) {
 		UseBuffered:   true,
 		FlushInterval: cfg.flushInterval,
 		FlushBytes:    cfg.flushBytes,
-		TagFormat:     statsd.InfixComma,
+		TagFormat:     tagFormat,
 	}
 	c, err := statsd.NewClientWithConfig(clientCfg)
 	if err != nil {
@@ -77,14 +84,34 @@ func New(addr, prefix string, opts ...Option) (*Statsd, error) {
 	return s, nil
 }
 
+// OnError sets the function called when sending a stat fails.
+func (s *Statsd) OnError(fn func(name string, tags [][2]string, err error)) {
+	s.errFn = fn
+}
+
 // Counter reports a counter value.
 func (s *Statsd) Counter(name string, v int64, tags [][2]string) {
 	if len(tags) == 0 {
-		_ = s.client.Inc(name, v, 1.0)
+		s.handleError(name, tags, s.client.Inc(name, v, 1.0))
+		return
+	}
+	withTags(tags, func(t []statsd.Tag) {
+		s.handleError(name, tags, s.client.Inc(name, v, 1.0, t...))
+	})
+}
+
+// SampledCounter reports a sampled counter value with its sample rate,
+// which the statsd server scales back up.
+//
+// The value is sent as is, as the client would otherwise sample it again.
+func (s *Statsd) SampledCounter(name string, v int64, rate float64, tags [][2]string) {
+	val := strconv.FormatInt(v, 10) + "|c|@" + strconv.FormatFloat(rate, 'f', -1, 64)
+	if len(tags) == 0 {
+		s.handleError(name, tags, s.client.Raw(name, val, 1.0))
 		return
 	}
 	withTags(tags, func(t []statsd.Tag) {
-		_ = s.client.Inc(name, v, 1.0, t...)
+		s.handleError(name, tags, s.client.Raw(name, val, 1.0, t...))
 	})
 }
 
@@ -95,22 +122,71 @@ func (s *Statsd) Counter(name string, v int64, tags [][2]string) {
 // nearest integer rather than silently truncated.
 func (s *Statsd) Gauge(name string, v float64, tags [][2]string) {
 	if len(tags) == 0 {
-		if s.es != nil {
-			_ = s.es.GaugeFloat(name, v, 1.0)
-		} else {
-			_ = s.client.Gauge(name, int64(math.Round(v)), 1.0)
-		}
+		s.handleError(name, tags, s.gauge(name, v, nil))
 		return
 	}
 	withTags(tags, func(t []statsd.Tag) {
-		if s.es != nil {
-			_ = s.es.GaugeFloat(name, v, 1.0, t...)
-		} else {
-			_ = s.client.Gauge(name, int64(math.Round(v)), 1.0, t...)
-		}
+		s.handleError(name, tags, s.gauge(name, v, t))
 	})
 }
 
+// Set returns a function sending values to a statsd set.
+func (s *Statsd) Set(name string, tags [][2]string) func(v string) {
+	if len(tags) == 0 {
+		return func(v string) {
+			s.handleError(name, tags, s.client.Set(name, v, 1.0))
+		}
+	}
+
+	t := fillTags(make([]statsd.Tag, 0, len(tags)), tags)
+	return func(v string) {
+		s.handleError(name, tags, s.client.Set(name, v, 1.0, t...))
+	}
+}
+
+// DogStatsD is a DogStatsD client, sending distributions to be
+// aggregated by the agent.
+//
+// Plain statsd servers do not support distributions, so the [Statsd]
+// client leaves them to be aggregated by the statter.
+type DogStatsD struct {
+	*Statsd
+}
+
+// NewDogStatsD returns a DogStatsD reporter, sending tags in the
+// DogStatsD "|#key:value" format.
+func NewDogStatsD(addr, prefix string, opts ...Option) (*DogStatsD, error) {
+	s, err := newStatsd(addr, prefix, statsd.SuffixOctothorpe, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DogStatsD{Statsd: s}, nil
+}
+
+// Distribution returns a function sending values to a DogStatsD
+// distribution, aggregated by the agent.
+func (s *DogStatsD) Distribution(name string, tags [][2]string) func(v float64) {
+	t := fillTags(make([]statsd.Tag, 0, len(tags)), tags)
+	return func(v float64) {
+		val := strconv.FormatFloat(v, 'f', -1, 64) + "|d"
+		s.handleError(name, tags, s.client.Raw(name, val, 1.0, t...))
+	}
+}
+
+func (s *Statsd) gauge(name string, v float64, t []statsd.Tag) error {
+	if s.es != nil {
+		return s.es.GaugeFloat(name, v, 1.0, t...)
+	}
+	return s.client.Gauge(name, int64(math.Round(v)), 1.0, t...)
+}
+
+func (s *Statsd) handleError(name string, tags [][2]string, err error) {
+	if err == nil || s.errFn == nil {
+		return
+	}
+	s.errFn(name, tags, err)
+}
+
 // Close closes the client and flushes buffered stats, if applicable.
 func (s *Statsd) Close() error {
 	return s.client.Close()
@@ -1,6 +1,8 @@
 package statsd
 
 import (
+	"errors"
+	"net"
 	"testing"
 	"time"
 
@@ -17,6 +19,10 @@ func TestNew(t *testing.T) {
 	t.Cleanup(func() { _ = s.Close() })
 
 	assert.Implements(t, (*statter.Reporter)(nil), s)
+	assert.Implements(t, (*statter.ErrorReporter)(nil), s)
+	assert.Implements(t, (*statter.SetReporter)(nil), s)
+	assert.NotImplements(t, (*statter.DistributionReporter)(nil), s)
+	assert.Implements(t, (*statter.SampledReporter)(nil), s)
 	assert.Equal(t, time.Second, s.cfg.flushInterval)
 	assert.Equal(t, 1, s.cfg.flushBytes)
 
@@ -24,6 +30,39 @@ func TestNew(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNewDogStatsD(t *testing.T) {
+	s, err := NewDogStatsD("127.0.0.1:1234", "test", WithFlushInterval(time.Second))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	assert.Implements(t, (*statter.Reporter)(nil), s)
+	assert.Implements(t, (*statter.SetReporter)(nil), s)
+	assert.Implements(t, (*statter.DistributionReporter)(nil), s)
+	assert.Equal(t, time.Second, s.cfg.flushInterval)
+
+	_, err = NewDogStatsD("127.0", "test")
+	assert.Error(t, err)
+}
+
+func TestNewDogStatsD_SendsSuffixTags(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	s, err := NewDogStatsD(conn.LocalAddr().String(), "test")
+	require.NoError(t, err)
+
+	s.Distribution("test", [][2]string{{"test", "test"}})(1.25)
+	err = s.Close()
+	require.NoError(t, err)
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "test.test:1.25|d|#test:test", string(buf[:n]))
+}
+
 func TestNew_Defaults(t *testing.T) {
 	s, err := New("127.0.0.1:1234", "test")
 	require.NoError(t, err)
@@ -54,6 +93,20 @@ func TestStatsd_Counter(t *testing.T) {
 	assert.Equal(t, "2", sent[0].Value)
 }
 
+func TestStatsd_SampledCounter(t *testing.T) {
+	sender := statsdtest.NewRecordingSender()
+	client, err := statsd.NewClientWithSender(sender, "test", statsd.InfixComma)
+	require.NoError(t, err)
+
+	s := &Statsd{client: client}
+
+	s.SampledCounter("test", 2, 0.1, [][2]string{{"test", "test"}})
+
+	sent := sender.GetSent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "test.test,test=test:2|c|@0.1", string(sent[0].Raw))
+}
+
 func TestStatsd_Gauge(t *testing.T) {
 	sender := statsdtest.NewRecordingSender()
 	client, err := statsd.NewClientWithSender(sender, "test", statsd.InfixComma)
@@ -72,6 +125,60 @@ func TestStatsd_Gauge(t *testing.T) {
 	assert.Equal(t, "2", sent[0].Value)
 }
 
+func TestStatsd_Set(t *testing.T) {
+	sender := statsdtest.NewRecordingSender()
+	client, err := statsd.NewClientWithSender(sender, "test", statsd.InfixComma)
+	require.NoError(t, err)
+
+	s := &Statsd{client: client}
+
+	fn := s.Set("test", [][2]string{{"test", "test"}})
+	fn("user-1")
+	fn("user-2")
+	s.Set("other", nil)("user-1")
+
+	sent := sender.GetSent()
+	require.Len(t, sent, 3)
+	assert.Equal(t, "test.test,test=test", sent[0].Stat)
+	assert.Equal(t, "user-1", sent[0].Value)
+	assert.Equal(t, "s", sent[0].Tag)
+	assert.Equal(t, "user-2", sent[1].Value)
+	assert.Equal(t, "test.other", sent[2].Stat)
+}
+
+func TestStatsd_Distribution(t *testing.T) {
+	sender := statsdtest.NewRecordingSender()
+	client, err := statsd.NewClientWithSender(sender, "test", statsd.SuffixOctothorpe)
+	require.NoError(t, err)
+
+	s := &DogStatsD{Statsd: &Statsd{client: client}}
+
+	s.Distribution("test", [][2]string{{"test", "test"}})(1.25)
+
+	sent := sender.GetSent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "test.test:1.25|d|#test:test", string(sent[0].Raw))
+}
+
+func TestStatsd_DistributionAggregatedLocally(t *testing.T) {
+	sender := statsdtest.NewRecordingSender()
+	client, err := statsd.NewClientWithSender(sender, "test", statsd.InfixComma)
+	require.NoError(t, err)
+
+	stats := statter.New(&Statsd{client: client}, 0)
+
+	stats.Distribution("test").Observe(1.25)
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	sent := sender.GetSent()
+	require.NotEmpty(t, sent)
+	for _, stat := range sent {
+		assert.NotEqual(t, "d", stat.Tag)
+	}
+}
+
 func TestStatsd_Gauge_PreservesDecimals(t *testing.T) {
 	sender := statsdtest.NewRecordingSender()
 	client, err := statsd.NewClientWithSender(sender, "test", statsd.InfixComma)
@@ -88,3 +195,38 @@ func TestStatsd_Gauge_PreservesDecimals(t *testing.T) {
 	assert.Len(t, sent, 1)
 	assert.Equal(t, "1.5", sent[0].Value) // was "1" with int64 truncation
 }
+
+func TestStatsd_OnError(t *testing.T) {
+	sendErr := errors.New("test error")
+	client, err := statsd.NewClientWithSender(errorSender{err: sendErr}, "test", statsd.InfixComma)
+	require.NoError(t, err)
+
+	s := &Statsd{client: client}
+
+	var (
+		gotName string
+		gotTags [][2]string
+		gotErr  error
+	)
+	s.OnError(func(name string, tags [][2]string, err error) {
+		gotName, gotTags, gotErr = name, tags, err
+	})
+
+	s.Counter("test", 2, [][2]string{{"test", "test"}})
+
+	assert.Equal(t, "test", gotName)
+	assert.Equal(t, [][2]string{{"test", "test"}}, gotTags)
+	assert.ErrorIs(t, gotErr, sendErr)
+}
+
+type errorSender struct {
+	err error
+}
+
+func (s errorSender) Send([]byte) (int, error) {
+	return 0, s.err
+}
+
+func (s errorSender) Close() error {
+	return nil
+}
@@ -2,6 +2,9 @@
 package victoriametrics
 
 import (
+	"bufio"
+	stdbytes "bytes"
+	"io"
 	"math"
 	"net/http"
 	"sort"
@@ -11,6 +14,7 @@ import (
 	"time"
 
 	"github.com/VictoriaMetrics/metrics"
+	"github.com/hamba/statter/v2"
 	"github.com/hamba/statter/v2/internal/bytes"
 )
 
@@ -20,6 +24,7 @@ type VictoriaMetrics struct {
 
 	mu     sync.RWMutex
 	gauges map[string]*gauge
+	help   map[string]string
 
 	set *metrics.Set
 }
@@ -32,16 +37,86 @@ func New() *VictoriaMetrics {
 		fqn:    fqn,
 		set:    metrics.NewSet(),
 		gauges: map[string]*gauge{},
+		help:   map[string]string{},
 	}
 }
 
 // Handler returns the VictoriaMetrics HTTP handler for scraping metrics in Prometheus format.
 func (m *VictoriaMetrics) Handler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
-		m.set.WritePrometheus(w)
+		m.mu.RLock()
+		hasHelp := len(m.help) > 0
+		m.mu.RUnlock()
+
+		if !hasHelp {
+			m.set.WritePrometheus(w)
+			return
+		}
+		m.writeWithHelp(w)
 	})
 }
 
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// Describe sets the help text of the metric with the given name, exposed as
+// a HELP line when scraping. The unit is appended to the help text.
+func (m *VictoriaMetrics) Describe(name string, meta statter.Metadata) {
+	help := meta.Help
+	if help == "" {
+		help = name
+	}
+	if meta.Unit != "" {
+		help += " (" + meta.Unit + ")"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.help[m.fqn.Format(name)] = helpEscaper.Replace(help)
+}
+
+// writeWithHelp writes the metrics in Prometheus format, adding a HELP
+// line before the first line of each described metric family.
+func (m *VictoriaMetrics) writeWithHelp(w io.Writer) {
+	var buf stdbytes.Buffer
+	m.set.WritePrometheus(&buf)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	var prev string
+	for line := range strings.Lines(buf.String()) {
+		name := line
+		if i := strings.IndexAny(line, "{ "); i >= 0 {
+			name = line[:i]
+		}
+
+		if !strings.HasPrefix(line, "#") {
+			if family, help, ok := m.lookupHelp(name); ok && family != prev {
+				prev = family
+				_, _ = bw.WriteString("# HELP " + family + " " + help + "\n")
+			}
+		}
+		_, _ = bw.WriteString(line)
+	}
+	_ = bw.Flush()
+}
+
+func (m *VictoriaMetrics) lookupHelp(name string) (family, help string, ok bool) {
+	if help, ok = m.help[name]; ok {
+		return name, help, true
+	}
+
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		if family, ok = strings.CutSuffix(name, suffix); ok {
+			help, ok = m.help[family]
+			return family, help, ok
+		}
+	}
+	return "", "", false
+}
+
 // Counter reports a counter value.
 func (m *VictoriaMetrics) Counter(name string, v int64, tags [][2]string) {
 	lbls := formatTags(tags, m.fqn)
@@ -114,6 +189,20 @@ func (m *VictoriaMetrics) setExistingGauge(key string, v float64) bool {
 	return false
 }
 
+// GaugeFunc registers a gauge whose value is returned by fn
+// when metrics are scraped.
+func (m *VictoriaMetrics) GaugeFunc(name string, fn func() float64, tags [][2]string) {
+	lbls := formatTags(tags, m.fqn)
+	key := createKey(name, lbls, m.fqn)
+
+	m.set.GetOrCreateGauge(key, fn)
+}
+
+// RemoveGaugeFunc removes the gauge function.
+func (m *VictoriaMetrics) RemoveGaugeFunc(name string, tags [][2]string) {
+	m.removeMetric(name, tags)
+}
+
 // Histogram reports a histogram value.
 func (m *VictoriaMetrics) Histogram(name string, tags [][2]string) func(v float64) {
 	lbls := formatTags(tags, m.fqn)
@@ -131,6 +220,17 @@ func (m *VictoriaMetrics) RemoveHistogram(name string, tags [][2]string) {
 	m.removeMetric(name, tags)
 }
 
+// Distribution reports a distribution value as a histogram, which can be
+// aggregated across instances by VictoriaMetrics.
+func (m *VictoriaMetrics) Distribution(name string, tags [][2]string) func(v float64) {
+	return m.Histogram(name, tags)
+}
+
+// RemoveDistribution removes a distribution.
+func (m *VictoriaMetrics) RemoveDistribution(name string, tags [][2]string) {
+	m.removeMetric(name, tags)
+}
+
 // Timing reports a timing value as a histogram in seconds.
 func (m *VictoriaMetrics) Timing(name string, tags [][2]string) func(v time.Duration) {
 	lbls := formatTags(tags, m.fqn)
@@ -188,7 +288,7 @@ func formatTags(tags [][2]string, fqn *fqn) string {
 		buf.WriteString(fqn.Format(tag[0]))
 		buf.WriteByte('=')
 		buf.WriteByte('"')
-		buf.WriteString(tag[1])
+		buf.WriteString(labelValueReplacer.Replace(tag[1]))
 		buf.WriteByte('"')
 	}
 
@@ -197,6 +297,9 @@ func formatTags(tags [][2]string, fqn *fqn) string {
 	return s
 }
 
+// labelValueReplacer escapes label values in the Prometheus text format.
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
 type fqn struct {
 	r *strings.Replacer
 }
@@ -3,6 +3,7 @@ package victoriametrics_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +31,10 @@ func TestNew(t *testing.T) {
 	assert.Implements(t, (*statter.RemovableHistogramReporter)(nil), p)
 	assert.Implements(t, (*statter.TimingReporter)(nil), p)
 	assert.Implements(t, (*statter.RemovableTimingReporter)(nil), p)
+	assert.Implements(t, (*statter.DescribingReporter)(nil), p)
+	assert.Implements(t, (*statter.GaugeFuncReporter)(nil), p)
+	assert.Implements(t, (*statter.DistributionReporter)(nil), p)
+	assert.Implements(t, (*statter.RemovableDistributionReporter)(nil), p)
 }
 
 func TestVictoriaMetrics_Counter(t *testing.T) {
@@ -88,6 +93,34 @@ func TestVictoriaMetrics_RemoveGauge(t *testing.T) {
 	assert.NotContains(t, rr.Body.String(), "test_test_test{foo=\"bar\"} 2.1")
 }
 
+func TestVictoriaMetrics_GaugeFunc(t *testing.T) {
+	p := victoriametrics.New()
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.GaugeFunc("test.test.test", func() float64 { return 2.1 }, [][2]string{{"test", "test"}, {"foo", "bar"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Body.String(), "test_test_test{foo=\"bar\",test=\"test\"} 2.1")
+}
+
+func TestVictoriaMetrics_RemoveGaugeFunc(t *testing.T) {
+	p := victoriametrics.New()
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.GaugeFunc("test.test.test", func() float64 { return 2.1 }, [][2]string{{"test", "test"}, {"foo", "bar"}})
+
+	p.RemoveGaugeFunc("test.test.test", [][2]string{{"test", "test"}, {"foo", "bar"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.NotContains(t, rr.Body.String(), "test_test_test{foo=\"bar\",test=\"test\"} 2.1")
+}
+
 func TestVictoriaMetrics_Histogram(t *testing.T) {
 	p := victoriametrics.New()
 	t.Cleanup(func() { _ = p.Close() })
@@ -152,6 +185,25 @@ func TestVictoriaMetrics_RemoveTiming(t *testing.T) {
 	assert.NotContains(t, rr.Body.String(), "test_test_test_count{foo=\"bar\"} 1")
 }
 
+func TestVictoriaMetrics_Describe(t *testing.T) {
+	p := victoriametrics.New()
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.Describe("test.counter", statter.Metadata{Help: "my counter", Unit: "requests"})
+	p.Describe("test.histo", statter.Metadata{Help: "my\nhistogram"})
+	p.Counter("test.counter", 2, [][2]string{{"foo", "bar"}})
+	p.Histogram("test.histo", [][2]string{{"foo", "bar"}})(2.3)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	p.Handler().ServeHTTP(rr, req)
+
+	body := rr.Body.String()
+	assert.Contains(t, body, "# HELP test_counter my counter (requests)\ntest_counter{foo=\"bar\"} 2")
+	assert.Contains(t, body, "# HELP test_histo my\\nhistogram\n")
+	assert.Equal(t, 1, strings.Count(body, "# HELP test_histo "))
+}
+
 func TestVictoriaMetrics_ConvertsLabels(t *testing.T) {
 	p := victoriametrics.New()
 	t.Cleanup(func() { _ = p.Close() })
@@ -165,6 +217,19 @@ func TestVictoriaMetrics_ConvertsLabels(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "foo_bar_baz{a=\"b\",test_label=\"test\"} 2")
 }
 
+func TestVictoriaMetrics_EscapesLabelValues(t *testing.T) {
+	p := victoriametrics.New()
+	t.Cleanup(func() { _ = p.Close() })
+
+	p.Counter("test", 2, [][2]string{{"foo", "a\"b\\c\nd"}})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/metrics", nil)
+	p.Handler().ServeHTTP(rr, req)
+
+	assert.Contains(t, rr.Body.String(), `test{foo="a\"b\\c\nd"} 2`)
+}
+
 func TestVictoriaMetrics_NoTags(t *testing.T) {
 	p := victoriametrics.New()
 	t.Cleanup(func() { _ = p.Close() })
@@ -0,0 +1,50 @@
+package statter
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// SampledReporter represents a stats reporter that handles sample rates.
+//
+// Counters created with a [SampleRate] are reported to it with the total
+// of the sampled increments and the sample rate, leaving the scaling to
+// the backend. Other reporters receive the total scaled by 1/rate.
+type SampledReporter interface {
+	SampledCounter(name string, v int64, rate float64, tags [][2]string)
+}
+
+// sampled determines if an event is recorded at the given sample rate,
+// where a zero rate records every event.
+func sampled(rate float64) bool {
+	return rate == 0 || rand.Float64() < rate
+}
+
+// scale scales a total of sampled events back up by the sample rate.
+func scale(v int64, rate float64) int64 {
+	if rate == 0 {
+		return v
+	}
+	return int64(math.Round(float64(v) / rate))
+}
+
+// scaleSum scales a sum of sampled values back up by the sample rate.
+func scaleSum(v, rate float64) float64 {
+	if rate == 0 {
+		return v
+	}
+	return v / rate
+}
+
+// reportCounter reports the sampled counter total v to r.
+func reportCounter(r Reporter, name string, v int64, rate float64, tags [][2]string) {
+	if rate == 0 {
+		r.Counter(name, v, tags)
+		return
+	}
+	if sr, ok := r.(SampledReporter); ok {
+		sr.SampledCounter(name, v, rate, tags)
+		return
+	}
+	r.Counter(name, scale(v, rate), tags)
+}
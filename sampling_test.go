@@ -0,0 +1,123 @@
+package statter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter_SampleRate(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	c := stats.Options(statter.SampleRate(0.5)).Counter("test", tags.Str("tag", "test"))
+	for range 10000 {
+		c.Inc(1)
+	}
+	r.Flush(t)
+
+	got, ok := r.CounterValue("test", []statter.Tag{tags.Str("tag", "test")})
+	require.True(t, ok)
+	assert.InDelta(t, 10000, got, 1000)
+}
+
+func TestCounter_SampleRateWithSampledReporter(t *testing.T) {
+	m := &mockSampledReporter{}
+	var got int64
+	m.On("SampledCounter", "test", mock.Anything, 0.5, [][2]string{}).Run(func(args mock.Arguments) {
+		got = args.Get(1).(int64)
+	}).Once()
+
+	stats := statter.New(m, 0)
+
+	c := stats.Options(statter.SampleRate(0.5)).Counter("test")
+	for range 10000 {
+		c.Inc(1)
+	}
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.InDelta(t, 5000, got, 500)
+	m.AssertExpectations(t)
+}
+
+func TestCounter_SampleRateIgnoresInvalidRates(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	stats.Options(statter.SampleRate(1.5)).Counter("test").Inc(10)
+	stats.Options(statter.SampleRate(0)).Counter("other").Inc(10)
+	r.Flush(t)
+
+	r.AssertCounter(t, "test", nil, 10)
+	r.AssertCounter(t, "other", nil, 10)
+}
+
+func TestCounter_SampleRateWithMultiReporter(t *testing.T) {
+	sampled := &mockSampledReporter{}
+	var got int64
+	sampled.On("SampledCounter", "test", mock.Anything, 0.5, [][2]string{}).Run(func(args mock.Arguments) {
+		got = args.Get(1).(int64)
+	}).Once()
+	r := statstest.NewReporter()
+
+	stats := statter.New(statter.MultiReporter(sampled, r), 0)
+
+	c := stats.Options(statter.SampleRate(0.5)).Counter("test")
+	for range 10000 {
+		c.Inc(1)
+	}
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	scaled, ok := r.CounterValue("test", nil)
+	require.True(t, ok)
+	assert.InDelta(t, float64(got)/0.5, scaled, 1)
+	sampled.AssertExpectations(t)
+}
+
+func TestHistogram_SampleRate(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	m.On("Gauge", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	stats := statter.New(m, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	h := stats.Options(statter.SampleRate(0.5)).Histogram("test")
+	for range 10000 {
+		h.Observe(2)
+	}
+
+	got := stats.Snapshot()
+	require.Len(t, got, 1)
+	require.NotNil(t, got[0].Summary)
+	assert.InDelta(t, 10000, got[0].Summary.Count, 1000)
+	assert.InDelta(t, 20000, got[0].Summary.Sum, 2000)
+	assert.Equal(t, 2.0, got[0].Summary.Mean)
+}
+
+func TestTiming_SampleRateNotAppliedWhenDelegated(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	tm := stats.Options(statter.SampleRate(0.5)).Timing("test")
+	for range 100 {
+		tm.Observe(time.Second)
+	}
+
+	assert.Len(t, r.TimingValues("test", nil), 100)
+}
+
+type mockSampledReporter struct {
+	mockSimpleReporter
+}
+
+func (r *mockSampledReporter) SampledCounter(name string, v int64, rate float64, tags [][2]string) {
+	_ = r.Called(name, v, rate, tags)
+}
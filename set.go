@@ -0,0 +1,154 @@
+package statter
+
+import (
+	"sync"
+
+	"github.com/hamba/statter/v2/internal/stats"
+)
+
+// HasSet determines if the set exists.
+func (s *Statter) HasSet(name string, tags ...Tag) bool {
+	k := s.seriesKey(name, tags)
+
+	_, ok := s.reg.sets.Load(k.String())
+
+	k.Release()
+
+	return ok
+}
+
+// Set returns a set for the given name and tags. The set is created on the
+// first call and the same instance is returned for subsequent calls with
+// identical name and tags.
+//
+// When the reporter implements [SetReporter], values are delegated to it
+// directly. Otherwise the number of unique values is estimated locally and
+// reported each interval as a gauge.
+func (s *Statter) Set(name string, tags ...Tag) *Set {
+	return s.set(name, tags, metricOptions{})
+}
+
+func (s *Statter) set(name string, tags []Tag, opts metricOptions) *Set {
+	if s.reg.closed.Load() {
+		return s.reg.nopSet
+	}
+
+	k := s.key(name, tags)
+
+	st, ok := loadSeries(s.reg, &s.reg.sets, k.String())
+	if !ok {
+		st, _ = newSeries(s, &s.reg.sets, k, name, tags, func(sr series) *Set {
+			s.reg.describe(sr.name, opts.meta)
+			set := newSet(s.reg.sr, s.reg.splitSet, sr.name, sr.tags)
+			set.meta = opts.meta
+			set.key = sr.key
+			set.reg = s.reg
+			set.overflow = sr.overflow
+			return set
+		})
+	}
+
+	k.Release()
+
+	return st
+}
+
+// Set implements a set counting unique values.
+//
+// When the reporter implements [SetReporter], values are delegated to it
+// directly. Otherwise the number of unique values added each interval is
+// estimated using a HyperLogLog sketch, with a standard error of about
+// 1.6%, and reported as a gauge.
+type Set struct {
+	activity
+
+	srFn     func(v string)
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
+
+	mu sync.Mutex
+	s  *stats.HyperLogLog
+}
+
+// newSet returns a set delegating to sr when it handles the set, estimating
+// locally otherwise. If aggregate is set, the set is estimated locally even
+// when delegated.
+func newSet(sr SetReporter, aggregate bool, name string, tags [][2]string) *Set {
+	s := &Set{
+		name: name,
+		tags: tags,
+	}
+	if sr != nil {
+		s.srFn = sr.Set(name, tags)
+	}
+	if s.srFn == nil || aggregate {
+		s.s = stats.NewHyperLogLog()
+	}
+
+	return s
+}
+
+// Add adds a value to the set. Values delegated to the reporter are
+// dropped once the statter is closed.
+func (s *Set) Add(v string) {
+	s.touch()
+
+	if s.srFn != nil {
+		if s.reg.closed.Load() {
+			// The reporter may be closed.
+			return
+		}
+		s.srFn(v)
+		if s.s == nil {
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.s.Add(v)
+	s.mu.Unlock()
+}
+
+// Delete removes the set. Delete is a no-op once the statter is closed.
+func (s *Set) Delete() {
+	if s.reg.closed.Load() {
+		return
+	}
+
+	if rsr, ok := s.reg.sr.(RemovableSetReporter); ok && s.srFn != nil {
+		rsr.RemoveSet(s.name, s.tags)
+	}
+	if rr, ok := s.reg.sa.(RemovableReporter); ok && s.s != nil {
+		rr.RemoveGauge(s.name, s.tags)
+	}
+	if v, ok := s.reg.sets.LoadAndDelete(s.key); ok {
+		v.deleted.Store(true)
+		s.reg.removed(s.name, s.overflow)
+	}
+}
+
+// value returns the estimated number of unique values, resetting the set.
+func (s *Set) value() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.s.Count()
+	s.s.Reset()
+	return n
+}
+
+// estimate returns the estimated number of unique values, and false
+// if the set is not estimated locally.
+func (s *Set) estimate() (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.s == nil {
+		return 0, false
+	}
+	return s.s.Count(), true
+}
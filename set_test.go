@@ -0,0 +1,167 @@
+package statter_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_Set(t *testing.T) {
+	m := &mockSetReporter{}
+	var got []string
+	m.On("Set", "test", [][2]string{{"tag", "test"}}).Return(func(v string) { got = append(got, v) })
+
+	stats := statter.New(m, 0)
+
+	s := stats.Set("test", tags.Str("tag", "test"))
+	s.Add("a")
+	s.Add("b")
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, got)
+	m.AssertExpectations(t)
+}
+
+func TestStatter_SetDoesNotDelegateAfterClose(t *testing.T) {
+	m := &mockSetReporter{}
+	var got []string
+	m.On("Set", "test", [][2]string{}).Return(func(v string) { got = append(got, v) })
+
+	stats := statter.New(m, 0)
+
+	s := stats.Set("test")
+	s.Add("a")
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	s.Add("b")
+
+	assert.Equal(t, []string{"a"}, got)
+	m.AssertExpectations(t)
+}
+
+func TestStatter_SetAggregated(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Gauge", "test", 3.0, [][2]string{{"tag", "test"}}).Once()
+	m.On("Gauge", "test", 1.0, [][2]string{{"tag", "test"}}).Once()
+	m.On("Gauge", "test", 0.0, [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	s := stats.Set("test", tags.Str("tag", "test"))
+	for _, v := range []string{"a", "b", "a", "c", "b"} {
+		s.Add(v)
+	}
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	s.Add("a")
+	err = stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_SetAggregatedEstimatesLargeSets(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	s := stats.Set("test")
+	for i := range 10000 {
+		s.Add(strconv.Itoa(i))
+	}
+
+	got := stats.Snapshot()
+
+	require.Len(t, got, 1)
+	assert.Equal(t, statter.MetricTypeSet, got[0].Type)
+	assert.InEpsilon(t, 10000, got[0].Value, 0.065)
+}
+
+func TestStatter_SetReturnsIdenticalSet(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	s := stats.Set("test", tags.Str("tag", "test"))
+
+	assert.Same(t, s, stats.Set("test", tags.Str("tag", "test")))
+	assert.True(t, stats.HasSet("test", tags.Str("tag", "test")))
+}
+
+func TestStatter_SetDelete(t *testing.T) {
+	m := &mockSetReporter{}
+	m.On("Set", "test", [][2]string{{"tag", "test"}}).Return(func(string) {})
+	m.On("RemoveSet", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	s := stats.Set("test", tags.Str("tag", "test"))
+	s.Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.False(t, stats.HasSet("test", tags.Str("tag", "test")))
+	m.AssertExpectations(t)
+}
+
+func TestStatter_SetAggregatedDelete(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.On("RemoveGauge", "test", [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	s := stats.Set("test", tags.Str("tag", "test"))
+	s.Delete()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_SetWithMultiReporter(t *testing.T) {
+	native := &mockSetReporter{}
+	native.On("Set", "test", [][2]string{}).Return(func(string) {})
+	agg := &mockSimpleReporter{}
+	agg.On("Gauge", "test", 1.0, [][2]string{}).Once()
+
+	stats := statter.New(statter.MultiReporter(native, agg), 0)
+
+	stats.Set("test").Add("a")
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	native.AssertExpectations(t)
+	agg.AssertExpectations(t)
+}
+
+type mockSetReporter struct {
+	mockSimpleReporter
+}
+
+func (r *mockSetReporter) Set(name string, tags [][2]string) func(v string) {
+	args := r.Called(name, tags)
+
+	ret := args.Get(0)
+	if ret == nil {
+		return nil
+	}
+	return ret.(func(v string))
+}
+
+func (r *mockSetReporter) RemoveSet(name string, tags [][2]string) {
+	_ = r.Called(name, tags)
+}
@@ -0,0 +1,63 @@
+package statter
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/hamba/statter/v2/internal/stats"
+)
+
+// sampleShard is a shard of a sharded sample, padded to a cache line
+// so observations on neighbouring shards do not contend.
+type sampleShard struct {
+	mu sync.Mutex
+	s  *stats.Sample
+
+	_ [48]byte
+}
+
+// shardedSample aggregates observations over several samples, so that
+// concurrent observations rarely share a lock. The shards are merged
+// when the sample is read.
+type shardedSample struct {
+	shards []sampleShard
+}
+
+func newShardedSample(shards, percSamples int) *shardedSample {
+	shards = max(shards, 1)
+	// Split the percentile samples between the shards, so memory does not
+	// grow with the number of shards.
+	percLimit := (percSamples + shards - 1) / shards
+
+	s := &shardedSample{shards: make([]sampleShard, shards)}
+	for i := range s.shards {
+		s.shards[i].s = stats.NewSample(percLimit)
+	}
+	return s
+}
+
+// Add adds a value to a random shard.
+func (s *shardedSample) Add(v float64) {
+	var i uint32
+	if n := len(s.shards); n > 1 {
+		i = rand.Uint32N(uint32(n))
+	}
+
+	sh := &s.shards[i]
+	sh.mu.Lock()
+	sh.s.Add(v)
+	sh.mu.Unlock()
+}
+
+// merge merges the shards into into, resetting them if reset is set.
+func (s *shardedSample) merge(into *stats.Sample, reset bool) {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		into.Merge(sh.s)
+		if reset {
+			sh.s.Reset()
+		}
+		sh.mu.Unlock()
+	}
+}
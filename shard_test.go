@@ -0,0 +1,68 @@
+package statter_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram_AggregatedConcurrently(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test_count", int64(8000), [][2]string{}).Once()
+	m.On("Gauge", "test_sum", 3996000.0, [][2]string{}).Once()
+	m.On("Gauge", "test_mean", 499.5, [][2]string{}).Once()
+	m.On("Gauge", "test_min", 0.0, [][2]string{}).Once()
+	m.On("Gauge", "test_max", 999.0, [][2]string{}).Once()
+	m.On("Gauge", mock.Anything, mock.Anything, [][2]string{})
+
+	stats := statter.New(m, time.Second, statter.WithAggregationShards(4))
+
+	h := stats.Histogram("test")
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				h.Observe(float64(i))
+			}
+		})
+	}
+	wg.Wait()
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestTiming_SnapshotMergesShards(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	m.On("Gauge", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	stats := statter.New(m, 0, statter.WithAggregationShards(4), statter.WithPercentiles([]float64{50}))
+	t.Cleanup(func() { _ = stats.Close() })
+
+	tm := stats.Timing("test")
+	for i := range 100 {
+		tm.Observe(time.Duration(i+1) * time.Millisecond)
+	}
+
+	got := stats.Snapshot()
+	require.Len(t, got, 1)
+	require.NotNil(t, got[0].Summary)
+	assert.Equal(t, int64(100), got[0].Summary.Count)
+	assert.Equal(t, 5050.0, got[0].Summary.Sum)
+	assert.Equal(t, 1.0, got[0].Summary.Min)
+	assert.Equal(t, 100.0, got[0].Summary.Max)
+	assert.Equal(t, map[float64]float64{50: 51}, got[0].Summary.Percentiles)
+
+	// Snapshots do not reset the shards.
+	got = stats.Snapshot()
+	require.Len(t, got, 1)
+	assert.Equal(t, int64(100), got[0].Summary.Count)
+}
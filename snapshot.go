@@ -0,0 +1,190 @@
+package statter
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/hamba/statter/v2/internal/stats"
+)
+
+// MetricType is the type of a metric.
+type MetricType string
+
+// Metric types.
+const (
+	MetricTypeCounter      MetricType = "counter"
+	MetricTypeGauge        MetricType = "gauge"
+	MetricTypeHistogram    MetricType = "histogram"
+	MetricTypeTiming       MetricType = "timing"
+	MetricTypeSet          MetricType = "set"
+	MetricTypeDistribution MetricType = "distribution"
+)
+
+// Series is a point-in-time view of a series held by a statter.
+type Series struct {
+	Name string
+	Tags [][2]string
+	Type MetricType
+	Meta Metadata
+
+	// Value is the counter value accumulated since the last report,
+	// or the current gauge value. For counter and gauge functions, it is
+	// the value returned by the last call. It is zero for histograms and
+	// timings. For sets, it is the estimated number of unique values added
+	// since the last report, and for distributions the number of values
+	// observed since the last report. Both are zero when values are
+	// delegated to the reporter.
+	Value float64
+
+	// Summary summarises the histogram or timing observations since
+	// the last report. Timings are summarised in milliseconds. It is nil
+	// for counters and gauges, when there are no observations, and when
+	// observations are delegated to the reporter.
+	Summary *Summary
+}
+
+// Summary is a summary of histogram or timing observations.
+type Summary struct {
+	Count  int64
+	Sum    float64
+	Mean   float64
+	StdDev float64
+	Min    float64
+	Max    float64
+
+	// Percentiles maps each configured percentile to its estimated value.
+	Percentiles map[float64]float64
+}
+
+type snapshotSeries struct {
+	key string
+	Series
+}
+
+// Snapshot returns a view of all series held by the statter, sorted by
+// name and tags. Taking a snapshot does not reset any values, and the
+// returned series are not affected by later updates.
+//
+// The snapshot covers the series of the root statter and all its
+// sub-statters.
+func (s *Statter) Snapshot() []Series {
+	return s.reg.snapshot()
+}
+
+func (r *registry) snapshot() []Series {
+	var ss []snapshotSeries
+	add := func(key, name string, tags [][2]string, typ MetricType, meta Metadata, val float64, sum *Summary) {
+		ss = append(ss, snapshotSeries{
+			key: key,
+			Series: Series{
+				Name:    name,
+				Tags:    slices.Clone(tags),
+				Type:    typ,
+				Meta:    meta,
+				Value:   val,
+				Summary: sum,
+			},
+		})
+	}
+
+	r.counters.Range(func(k string, c *Counter) bool {
+		add(k, c.name, c.tags, MetricTypeCounter, c.meta, float64(scale(c.val.Load(), c.rate)), nil)
+		return true
+	})
+
+	r.counterFuncs.Range(func(k string, c *CounterFunc) bool {
+		add(k, c.name, c.tags, MetricTypeCounter, c.meta, float64(c.last.Load()), nil)
+		return true
+	})
+
+	r.gauges.Range(func(k string, g *Gauge) bool {
+		add(k, g.name, g.tags, MetricTypeGauge, g.meta, g.value(), nil)
+		return true
+	})
+
+	r.gaugeFuncs.Range(func(k string, g *GaugeFunc) bool {
+		add(k, g.name, g.tags, MetricTypeGauge, g.meta, g.lastValue(), nil)
+		return true
+	})
+
+	r.histograms.Range(func(k string, h *Histogram) bool {
+		add(k, h.name, h.tags, MetricTypeHistogram, h.meta, 0, h.summary(r.cfg.percentiles))
+		return true
+	})
+
+	r.timings.Range(func(k string, t *Timing) bool {
+		add(k, t.name, t.tags, MetricTypeTiming, t.meta, 0, t.summary(r.cfg.percentiles))
+		return true
+	})
+
+	r.sets.Range(func(k string, s *Set) bool {
+		n, _ := s.estimate()
+		add(k, s.name, s.tags, MetricTypeSet, s.meta, float64(n), nil)
+		return true
+	})
+
+	r.distributions.Range(func(k string, d *Distribution) bool {
+		add(k, d.name, d.tags, MetricTypeDistribution, d.meta, float64(d.count()), nil)
+		return true
+	})
+
+	slices.SortFunc(ss, func(a, b snapshotSeries) int {
+		return cmp.Or(
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.key, b.key),
+		)
+	})
+
+	series := make([]Series, len(ss))
+	for i, s := range ss {
+		series[i] = s.Series
+	}
+	return series
+}
+
+func (h *Histogram) summary(ps []float64) *Summary {
+	if h.s == nil {
+		return nil
+	}
+
+	s := h.reg.pool.Get()
+	defer h.reg.pool.Put(s)
+
+	h.s.merge(s, false)
+	return newSummary(s, ps, h.rate)
+}
+
+func (t *Timing) summary(ps []float64) *Summary {
+	if t.s == nil {
+		return nil
+	}
+
+	s := t.reg.pool.Get()
+	defer t.reg.pool.Put(s)
+
+	t.s.merge(s, false)
+	return newSummary(s, ps, t.rate)
+}
+
+func newSummary(s *stats.Sample, ps []float64, rate float64) *Summary {
+	if s.Count() == 0 {
+		return nil
+	}
+
+	vs := s.Percentiles(ps)
+	perc := make(map[float64]float64, len(vs))
+	for i, v := range vs {
+		perc[ps[i]] = v
+	}
+
+	return &Summary{
+		Count:       scale(s.Count(), rate),
+		Sum:         scaleSum(s.Sum(), rate),
+		Mean:        s.Mean(),
+		StdDev:      s.StdDev(),
+		Min:         s.Min(),
+		Max:         s.Max(),
+		Percentiles: perc,
+	}
+}
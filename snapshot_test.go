@@ -0,0 +1,132 @@
+package statter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_Snapshot(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	m.On("Gauge", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	stats := statter.New(m, 0, statter.WithPrefix("prefix"))
+	t.Cleanup(func() { _ = stats.Close() })
+
+	stats.Options(statter.Help("my counter")).Counter("counter", tags.Str("b", "2")).Inc(2)
+	stats.Counter("counter", tags.Str("a", "1")).Inc(3)
+	stats.With("sub").Gauge("gauge").Set(1.5)
+	h := stats.Histogram("histo")
+	for _, v := range []float64{10, 20, 30} {
+		h.Observe(v)
+	}
+	stats.Timing("timing").Observe(time.Second)
+	stats.Histogram("empty")
+
+	got := stats.Snapshot()
+
+	want := []statter.Series{
+		{Name: "prefix.counter", Tags: [][2]string{{"a", "1"}}, Type: statter.MetricTypeCounter, Value: 3},
+		{
+			Name:  "prefix.counter",
+			Tags:  [][2]string{{"b", "2"}},
+			Type:  statter.MetricTypeCounter,
+			Meta:  statter.Metadata{Help: "my counter"},
+			Value: 2,
+		},
+		{Name: "prefix.empty", Tags: [][2]string{}, Type: statter.MetricTypeHistogram},
+		{
+			Name: "prefix.histo",
+			Tags: [][2]string{},
+			Type: statter.MetricTypeHistogram,
+			Summary: &statter.Summary{
+				Count:       3,
+				Sum:         60,
+				Mean:        20,
+				StdDev:      8.16496580927726,
+				Min:         10,
+				Max:         30,
+				Percentiles: map[float64]float64{10: 10, 90: 30},
+			},
+		},
+		{Name: "prefix.sub.gauge", Tags: [][2]string{}, Type: statter.MetricTypeGauge, Value: 1.5},
+		{
+			Name: "prefix.timing",
+			Tags: [][2]string{},
+			Type: statter.MetricTypeTiming,
+			Summary: &statter.Summary{
+				Count:       1,
+				Sum:         1000,
+				Mean:        1000,
+				Min:         1000,
+				Max:         1000,
+				Percentiles: map[float64]float64{10: 1000, 90: 1000},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestStatter_SnapshotDoesNotResetValues(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(2), [][2]string{}).Once()
+	m.On("Counter", "histo_count", int64(1), [][2]string{}).Once()
+	m.On("Gauge", mock.Anything, mock.Anything, [][2]string{})
+
+	stats := statter.New(m, 0)
+
+	stats.Counter("test").Inc(2)
+	stats.Histogram("histo").Observe(5)
+
+	_ = stats.Snapshot()
+	_ = stats.Snapshot()
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	got := stats.Snapshot()
+	require.Len(t, got, 2)
+	assert.Zero(t, got[1].Value)
+	assert.Nil(t, got[0].Summary)
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_SnapshotIsNotAffectedByUpdates(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	g := stats.Gauge("test", tags.Str("tag", "test"))
+	g.Set(1)
+
+	got := stats.Snapshot()
+	got[0].Tags[0][1] = "changed"
+	g.Set(2)
+
+	assert.Equal(t, 1.0, got[0].Value)
+	assert.True(t, stats.HasGauge("test", tags.Str("tag", "test")))
+}
+
+func TestStatter_SnapshotDelegatedHistogramHasNoSummary(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.On("Histogram", "test", [][2]string{}).Return(func(float64) {})
+
+	stats := statter.New(m, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	stats.Histogram("test").Observe(1)
+
+	got := stats.Snapshot()
+
+	require.Len(t, got, 1)
+	assert.Nil(t, got[0].Summary)
+}
@@ -0,0 +1,141 @@
+package statstest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hamba/statter/v2"
+)
+
+// Clock is a fake clock that only moves when advanced, for use with
+// [statter.WithClock].
+//
+// Timers fire when the clock is advanced past their deadline. As the report
+// loop of a statter runs in its own goroutine, a test advancing the clock to
+// trigger a flush should wait for the loop to arm its next timer, which it
+// does once the flush is complete:
+//
+//	clock.BlockUntilTimers(1)
+//	clock.Advance(10 * time.Second)
+//	clock.BlockUntilTimers(1)
+type Clock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*timer
+}
+
+// NewClock returns a fake clock set to now.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer returns a timer firing once the clock is advanced by d.
+func (c *Clock) NewTimer(d time.Duration) statter.Timer {
+	t := &timer{c: c, ch: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// Advance advances the clock by d, firing the timers due by then
+// in order of their deadline.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	end := c.now.Add(d)
+	for {
+		t := c.nextTimer(end)
+		if t == nil {
+			break
+		}
+		c.now = t.deadline
+		c.stop(t)
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+	}
+	c.now = end
+}
+
+// BlockUntilTimers blocks until at least n timers are armed.
+func (c *Clock) BlockUntilTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// nextTimer returns the armed timer with the earliest deadline not
+// after end, or nil if there is none.
+func (c *Clock) nextTimer(end time.Time) *timer {
+	var next *timer
+	for _, t := range c.timers {
+		if t.deadline.After(end) {
+			continue
+		}
+		if next == nil || t.deadline.Before(next.deadline) {
+			next = t
+		}
+	}
+	return next
+}
+
+// stop disarms t, returning true if it was armed.
+func (c *Clock) stop(t *timer) bool {
+	for i, armed := range c.timers {
+		if armed == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type timer struct {
+	c        *Clock
+	ch       chan time.Time
+	deadline time.Time
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	armed := t.c.stop(t)
+	t.deadline = t.c.now.Add(d)
+	if d <= 0 {
+		select {
+		case t.ch <- t.c.now:
+		default:
+		}
+		return armed
+	}
+	t.c.timers = append(t.c.timers, t)
+	t.c.cond.Broadcast()
+	return armed
+}
+
+func (t *timer) Stop() bool {
+	t.c.mu.Lock()
+	defer t.c.mu.Unlock()
+
+	return t.c.stop(t)
+}
@@ -0,0 +1,82 @@
+package statstest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock_Now(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := statstest.NewClock(now)
+
+	assert.Equal(t, now, clock.Now())
+
+	clock.Advance(time.Second)
+
+	assert.Equal(t, now.Add(time.Second), clock.Now())
+}
+
+func TestClock_TimerFiresWhenAdvanced(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := statstest.NewClock(now)
+
+	timer := clock.NewTimer(time.Second)
+
+	clock.Advance(999 * time.Millisecond)
+	assertNotFired(t, timer.C())
+
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, now.Add(time.Second), <-timer.C())
+}
+
+func TestClock_TimerReset(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := statstest.NewClock(now)
+
+	timer := clock.NewTimer(time.Second)
+
+	assert.True(t, timer.Reset(2*time.Second))
+
+	clock.Advance(time.Second)
+	assertNotFired(t, timer.C())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, now.Add(2*time.Second), <-timer.C())
+	assert.False(t, timer.Reset(0))
+	assert.Equal(t, now.Add(2*time.Second), <-timer.C())
+}
+
+func TestClock_TimerStop(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	timer := clock.NewTimer(time.Second)
+
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+
+	clock.Advance(time.Second)
+	assertNotFired(t, timer.C())
+}
+
+func TestClock_BlockUntilTimers(t *testing.T) {
+	clock := statstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	go func() {
+		clock.NewTimer(time.Second)
+	}()
+
+	clock.BlockUntilTimers(1)
+}
+
+func assertNotFired(t *testing.T, ch <-chan time.Time) {
+	t.Helper()
+
+	select {
+	case <-ch:
+		assert.Fail(t, "unexpected timer fire")
+	default:
+	}
+}
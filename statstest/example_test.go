@@ -0,0 +1,19 @@
+package statstest_test
+
+import (
+	"testing"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+)
+
+func ExampleNewStatter() {
+	var t testing.TB // Provided by the test.
+
+	stats, reporter := statstest.NewStatter(t, statter.WithPrefix("my-prefix"))
+
+	stats.Counter("my-counter", tags.Str("tag", "value")).Inc(1)
+
+	reporter.AssertCounter(t, "my-prefix.my-counter", []statter.Tag{tags.Str("tag", "value")}, 1)
+}
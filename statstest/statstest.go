@@ -0,0 +1,356 @@
+// Package statstest implements a recording reporter and assertions
+// for testing code instrumented with statter.
+package statstest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+)
+
+// NewStatter returns a statter in manual mode reporting to a new recording
+// reporter. Assertions on the returned reporter flush the statter first,
+// so tests never wait for a reporting interval. The statter is closed
+// when the test completes.
+func NewStatter(t testing.TB, opts ...statter.Option) (*statter.Statter, *Reporter) {
+	t.Helper()
+
+	r := NewReporter()
+	s := statter.New(r, 0, opts...)
+	r.flush = s.Flush
+
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s, r
+}
+
+// Reporter is a concurrency-safe reporter recording all reported stats.
+//
+// Counter values are accumulated across reports, gauges hold their last
+// reported value, and histogram and timing observations are recorded in
+// order.
+type Reporter struct {
+	flush func(context.Context) error
+
+	mu         sync.Mutex
+	counters   map[string]int64
+	gauges     map[string]float64
+	histograms map[string][]float64
+	timings    map[string][]time.Duration
+}
+
+// NewReporter returns a recording reporter.
+func NewReporter() *Reporter {
+	return &Reporter{
+		counters:   map[string]int64{},
+		gauges:     map[string]float64{},
+		histograms: map[string][]float64{},
+		timings:    map[string][]time.Duration{},
+	}
+}
+
+// Counter records a counter value.
+func (r *Reporter) Counter(name string, v int64, tags [][2]string) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	r.counters[k] += v
+	r.mu.Unlock()
+}
+
+// RemoveCounter removes a recorded counter.
+func (r *Reporter) RemoveCounter(name string, tags [][2]string) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	delete(r.counters, k)
+	r.mu.Unlock()
+}
+
+// Gauge records a gauge value.
+func (r *Reporter) Gauge(name string, v float64, tags [][2]string) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	r.gauges[k] = v
+	r.mu.Unlock()
+}
+
+// RemoveGauge removes a recorded gauge.
+func (r *Reporter) RemoveGauge(name string, tags [][2]string) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	delete(r.gauges, k)
+	r.mu.Unlock()
+}
+
+// Histogram returns a function recording histogram observations.
+func (r *Reporter) Histogram(name string, tags [][2]string) func(v float64) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	if _, ok := r.histograms[k]; !ok {
+		r.histograms[k] = []float64{}
+	}
+	r.mu.Unlock()
+
+	return func(v float64) {
+		r.mu.Lock()
+		r.histograms[k] = append(r.histograms[k], v)
+		r.mu.Unlock()
+	}
+}
+
+// RemoveHistogram removes a recorded histogram.
+func (r *Reporter) RemoveHistogram(name string, tags [][2]string) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	delete(r.histograms, k)
+	r.mu.Unlock()
+}
+
+// Timing returns a function recording timing observations.
+func (r *Reporter) Timing(name string, tags [][2]string) func(v time.Duration) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	if _, ok := r.timings[k]; !ok {
+		r.timings[k] = []time.Duration{}
+	}
+	r.mu.Unlock()
+
+	return func(v time.Duration) {
+		r.mu.Lock()
+		r.timings[k] = append(r.timings[k], v)
+		r.mu.Unlock()
+	}
+}
+
+// RemoveTiming removes a recorded timing.
+func (r *Reporter) RemoveTiming(name string, tags [][2]string) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	delete(r.timings, k)
+	r.mu.Unlock()
+}
+
+// CounterValue returns the accumulated value of a counter, and whether it
+// has been reported.
+func (r *Reporter) CounterValue(name string, tags []statter.Tag) (int64, bool) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.counters[k]
+	return v, ok
+}
+
+// GaugeValue returns the last reported value of a gauge, and whether it
+// has been reported.
+func (r *Reporter) GaugeValue(name string, tags []statter.Tag) (float64, bool) {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.gauges[k]
+	return v, ok
+}
+
+// HistogramValues returns a copy of the observations of a histogram.
+func (r *Reporter) HistogramValues(name string, tags []statter.Tag) []float64 {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.histograms[k])
+}
+
+// TimingValues returns a copy of the observations of a timing.
+func (r *Reporter) TimingValues(name string, tags []statter.Tag) []time.Duration {
+	k := seriesKey(name, tags)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.timings[k])
+}
+
+// Reset removes all recorded stats.
+func (r *Reporter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clear(r.counters)
+	clear(r.gauges)
+	clear(r.histograms)
+	clear(r.timings)
+}
+
+// Flush flushes the attached statter, if any, so that all pending stats
+// are recorded. Flushing a closed statter is a no-op, as closing it
+// already flushed all its stats.
+func (r *Reporter) Flush(t testing.TB) {
+	t.Helper()
+
+	if r.flush == nil {
+		return
+	}
+	if err := r.flush(context.Background()); err != nil && !errors.Is(err, statter.ErrClosed) {
+		t.Errorf("statstest: could not flush statter: %v", err)
+	}
+}
+
+// AssertCounter asserts that the accumulated value of a counter equals want.
+func (r *Reporter) AssertCounter(t testing.TB, name string, tags []statter.Tag, want int64) bool {
+	t.Helper()
+
+	r.Flush(t)
+
+	got, ok := r.CounterValue(name, tags)
+	if !ok {
+		t.Errorf("statstest: counter %s was not reported", formatSeries(name, tags))
+		return false
+	}
+	if got != want {
+		t.Errorf("statstest: counter %s is %d, want %d", formatSeries(name, tags), got, want)
+		return false
+	}
+	return true
+}
+
+// AssertGauge asserts that the last value of a gauge equals want.
+func (r *Reporter) AssertGauge(t testing.TB, name string, tags []statter.Tag, want float64) bool {
+	t.Helper()
+
+	r.Flush(t)
+
+	got, ok := r.GaugeValue(name, tags)
+	if !ok {
+		t.Errorf("statstest: gauge %s was not reported", formatSeries(name, tags))
+		return false
+	}
+	if got != want {
+		t.Errorf("statstest: gauge %s is %g, want %g", formatSeries(name, tags), got, want)
+		return false
+	}
+	return true
+}
+
+// AssertGaugeEventually asserts that the value of a gauge equals want
+// within the given timeout, flushing and checking every tick.
+func (r *Reporter) AssertGaugeEventually(t testing.TB, name string, tags []statter.Tag, want float64, timeout, tick time.Duration) bool {
+	t.Helper()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		r.Flush(t)
+
+		got, ok := r.GaugeValue(name, tags)
+		if ok && got == want {
+			return true
+		}
+
+		select {
+		case <-timer.C:
+			if !ok {
+				t.Errorf("statstest: gauge %s was not reported within %s", formatSeries(name, tags), timeout)
+				return false
+			}
+			t.Errorf("statstest: gauge %s is %g, want %g within %s", formatSeries(name, tags), got, want, timeout)
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// AssertHistogram asserts that the observations of a histogram equal want.
+func (r *Reporter) AssertHistogram(t testing.TB, name string, tags []statter.Tag, want ...float64) bool {
+	t.Helper()
+
+	r.Flush(t)
+
+	if got := r.HistogramValues(name, tags); !slices.Equal(got, want) {
+		t.Errorf("statstest: histogram %s observed %v, want %v", formatSeries(name, tags), got, want)
+		return false
+	}
+	return true
+}
+
+// AssertTiming asserts that the observations of a timing equal want.
+func (r *Reporter) AssertTiming(t testing.TB, name string, tags []statter.Tag, want ...time.Duration) bool {
+	t.Helper()
+
+	r.Flush(t)
+
+	if got := r.TimingValues(name, tags); !slices.Equal(got, want) {
+		t.Errorf("statstest: timing %s observed %v, want %v", formatSeries(name, tags), got, want)
+		return false
+	}
+	return true
+}
+
+// AssertNoCounter asserts that a counter has not been reported.
+func (r *Reporter) AssertNoCounter(t testing.TB, name string, tags []statter.Tag) bool {
+	t.Helper()
+
+	r.Flush(t)
+
+	if got, ok := r.CounterValue(name, tags); ok {
+		t.Errorf("statstest: counter %s was reported with %d", formatSeries(name, tags), got)
+		return false
+	}
+	return true
+}
+
+func seriesKey(name string, tags [][2]string) string {
+	tags = slices.Clone(tags)
+	slices.SortFunc(tags, func(a, b [2]string) int {
+		return strings.Compare(a[0], b[0])
+	})
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	for _, tag := range tags {
+		sb.WriteByte(0)
+		sb.WriteString(tag[0])
+		sb.WriteByte(0)
+		sb.WriteString(tag[1])
+	}
+	return sb.String()
+}
+
+func formatSeries(name string, tags [][2]string) string {
+	if len(tags) == 0 {
+		return name
+	}
+
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte('{')
+	for i, tag := range tags {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(tag[0])
+		sb.WriteByte('=')
+		sb.WriteString(tag[1])
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
@@ -0,0 +1,222 @@
+package statstest_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter_ImplementsInterfaces(t *testing.T) {
+	r := statstest.NewReporter()
+
+	assert.Implements(t, (*statter.Reporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableReporter)(nil), r)
+	assert.Implements(t, (*statter.HistogramReporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableHistogramReporter)(nil), r)
+	assert.Implements(t, (*statter.TimingReporter)(nil), r)
+	assert.Implements(t, (*statter.RemovableTimingReporter)(nil), r)
+}
+
+func TestReporter_Records(t *testing.T) {
+	r := statstest.NewReporter()
+
+	r.Counter("counter", 2, [][2]string{{"b", "2"}, {"a", "1"}})
+	r.Counter("counter", 3, [][2]string{{"a", "1"}, {"b", "2"}})
+	r.Gauge("gauge", 1, nil)
+	r.Gauge("gauge", 2, nil)
+	h := r.Histogram("histo", nil)
+	h(1)
+	h(2)
+	r.Timing("timing", nil)(time.Second)
+
+	got, ok := r.CounterValue("counter", []statter.Tag{{"a", "1"}, {"b", "2"}})
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), got)
+	gauge, ok := r.GaugeValue("gauge", nil)
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, gauge)
+	assert.Equal(t, []float64{1, 2}, r.HistogramValues("histo", nil))
+	assert.Equal(t, []time.Duration{time.Second}, r.TimingValues("timing", nil))
+}
+
+func TestReporter_Removes(t *testing.T) {
+	r := statstest.NewReporter()
+
+	r.Counter("counter", 2, nil)
+	r.Gauge("gauge", 1, nil)
+	r.Histogram("histo", nil)(1)
+	r.Timing("timing", nil)(time.Second)
+
+	r.RemoveCounter("counter", nil)
+	r.RemoveGauge("gauge", nil)
+	r.RemoveHistogram("histo", nil)
+	r.RemoveTiming("timing", nil)
+
+	_, ok := r.CounterValue("counter", nil)
+	assert.False(t, ok)
+	_, ok = r.GaugeValue("gauge", nil)
+	assert.False(t, ok)
+	assert.Empty(t, r.HistogramValues("histo", nil))
+	assert.Empty(t, r.TimingValues("timing", nil))
+}
+
+func TestReporter_Reset(t *testing.T) {
+	r := statstest.NewReporter()
+
+	r.Counter("counter", 2, nil)
+	r.Gauge("gauge", 1, nil)
+
+	r.Reset()
+
+	_, ok := r.CounterValue("counter", nil)
+	assert.False(t, ok)
+	_, ok = r.GaugeValue("gauge", nil)
+	assert.False(t, ok)
+}
+
+func TestReporter_IsConcurrencySafe(t *testing.T) {
+	s, r := statstest.NewStatter(t)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			for range 100 {
+				s.Counter("counter").Inc(1)
+				s.Gauge("gauge", tags.Int("i", i)).Set(1)
+				s.Histogram("histo").Observe(1)
+				s.Timing("timing").Observe(time.Millisecond)
+			}
+		})
+		wg.Go(func() {
+			r.Flush(t)
+		})
+	}
+	wg.Wait()
+
+	r.AssertCounter(t, "counter", nil, 1000)
+	assert.Len(t, r.HistogramValues("histo", nil), 1000)
+	assert.Len(t, r.TimingValues("timing", nil), 1000)
+}
+
+func TestNewStatter(t *testing.T) {
+	s, r := statstest.NewStatter(t, statter.WithPrefix("prefix"))
+
+	s.Counter("counter", tags.Str("tag", "test")).Inc(2)
+	s.Gauge("gauge").Set(1.5)
+	s.Histogram("histo").Observe(2)
+	s.Timing("timing").Observe(time.Second)
+
+	r.AssertCounter(t, "prefix.counter", []statter.Tag{tags.Str("tag", "test")}, 2)
+	r.AssertGauge(t, "prefix.gauge", nil, 1.5)
+	r.AssertHistogram(t, "prefix.histo", nil, 2)
+	r.AssertTiming(t, "prefix.timing", nil, time.Second)
+	r.AssertNoCounter(t, "prefix.other", nil)
+}
+
+func TestNewStatter_AssertsAfterClose(t *testing.T) {
+	s, r := statstest.NewStatter(t)
+
+	s.Counter("counter").Inc(2)
+	s.Histogram("histo").Observe(2)
+
+	err := s.Close()
+	require.NoError(t, err)
+
+	r.AssertCounter(t, "counter", nil, 2)
+	r.AssertHistogram(t, "histo", nil, 2)
+}
+
+func TestReporter_AssertGaugeEventually(t *testing.T) {
+	s, r := statstest.NewStatter(t)
+
+	g := s.Gauge("gauge")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		g.Set(2)
+	}()
+
+	r.AssertGaugeEventually(t, "gauge", nil, 2, time.Second, time.Millisecond)
+}
+
+func TestReporter_AssertionsFail(t *testing.T) {
+	s, r := statstest.NewStatter(t)
+
+	s.Counter("counter").Inc(2)
+	s.Gauge("gauge").Set(1)
+	s.Histogram("histo").Observe(1)
+	s.Timing("timing").Observe(time.Second)
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB) bool
+		want   string
+	}{
+		{
+			name:   "counter value",
+			assert: func(t testing.TB) bool { return r.AssertCounter(t, "counter", nil, 3) },
+			want:   "statstest: counter counter is 2, want 3",
+		},
+		{
+			name:   "counter missing",
+			assert: func(t testing.TB) bool { return r.AssertCounter(t, "other", []statter.Tag{{"a", "b"}}, 3) },
+			want:   "statstest: counter other{a=b} was not reported",
+		},
+		{
+			name:   "gauge value",
+			assert: func(t testing.TB) bool { return r.AssertGauge(t, "gauge", nil, 2) },
+			want:   "statstest: gauge gauge is 1, want 2",
+		},
+		{
+			name: "gauge eventually",
+			assert: func(t testing.TB) bool {
+				return r.AssertGaugeEventually(t, "gauge", nil, 2, 10*time.Millisecond, time.Millisecond)
+			},
+			want: "statstest: gauge gauge is 1, want 2 within 10ms",
+		},
+		{
+			name:   "histogram",
+			assert: func(t testing.TB) bool { return r.AssertHistogram(t, "histo", nil, 2) },
+			want:   "statstest: histogram histo observed [1], want [2]",
+		},
+		{
+			name:   "timing",
+			assert: func(t testing.TB) bool { return r.AssertTiming(t, "timing", nil) },
+			want:   "statstest: timing timing observed [1s], want []",
+		},
+		{
+			name:   "no counter",
+			assert: func(t testing.TB) bool { return r.AssertNoCounter(t, "counter", nil) },
+			want:   "statstest: counter counter was reported with 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ft := &fakeT{TB: t}
+
+			ok := test.assert(ft)
+
+			assert.False(t, ok)
+			assert.Equal(t, []string{test.want}, ft.errs)
+		})
+	}
+}
+
+type fakeT struct {
+	testing.TB
+
+	errs []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
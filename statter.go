@@ -1,12 +1,14 @@
 package statter
 
 import (
+	"context"
 	"io"
 	"math"
-	"sync"
+	"runtime"
 	"sync/atomic"
 	"time"
 
+	"github.com/go4org/hashtriemap"
 	"github.com/hamba/statter/v2/internal/stats"
 )
 
@@ -45,6 +47,44 @@ type RemovableTimingReporter interface {
 	RemoveTiming(name string, tags [][2]string)
 }
 
+// ErrorReporter represents a stats reporter that surfaces errors.
+//
+// OnError is called once, before any stats are reported, with the function
+// the reporter should call for every error encountered while registering
+// or sending a metric.
+type ErrorReporter interface {
+	OnError(fn func(name string, tags [][2]string, err error))
+}
+
+// SetReporter represents a stats reporter that handles sets of unique values.
+type SetReporter interface {
+	Set(name string, tags [][2]string) func(v string)
+}
+
+// RemovableSetReporter represents a stats reporter that handles set removal.
+type RemovableSetReporter interface {
+	RemoveSet(name string, tags [][2]string)
+}
+
+// DistributionReporter represents a stats reporter that handles
+// distributions, aggregating the values in the backend.
+type DistributionReporter interface {
+	Distribution(name string, tags [][2]string) func(v float64)
+}
+
+// RemovableDistributionReporter represents a stats reporter that handles
+// distribution removal.
+type RemovableDistributionReporter interface {
+	RemoveDistribution(name string, tags [][2]string)
+}
+
+// GaugeFuncReporter represents a stats reporter that evaluates gauge
+// functions itself, such as when metrics are scraped.
+type GaugeFuncReporter interface {
+	GaugeFunc(name string, fn func() float64, tags [][2]string)
+	RemoveGaugeFunc(name string, tags [][2]string)
+}
+
 // Tag is a stat tag.
 type Tag = [2]string
 
@@ -54,6 +94,19 @@ type config struct {
 	separator   string
 	percSamples int
 	percentiles []float64
+	shards      int
+	errHandler  func(error)
+
+	maxSeries       int
+	maxNameSeries   int
+	seriesTTL       time.Duration
+	callbackTimeout time.Duration
+	distBuckets     []float64
+	traceIDFn       func(ctx context.Context) string
+	alignFlush      bool
+	flushJitter     time.Duration
+	tagPolicy       *tagPolicy
+	clock           Clock
 }
 
 func defaultConfig() config {
@@ -61,6 +114,11 @@ func defaultConfig() config {
 		separator:   ".",
 		percSamples: 1000,
 		percentiles: []float64{10, 90},
+		shards:      runtime.GOMAXPROCS(0),
+
+		callbackTimeout: time.Second,
+		distBuckets:     DefaultDistributionBuckets,
+		clock:           systemClock{},
 	}
 }
 
@@ -104,6 +162,82 @@ func WithPercentiles(p []float64) Option {
 	}
 }
 
+// WithAggregationShards sets the number of shards locally aggregated
+// histograms and timings spread their observations over. More shards
+// reduce lock contention between concurrent observations, at the cost of
+// memory per series. The percentile samples are split between the shards.
+// Defaults to GOMAXPROCS; values less than one are treated as one.
+func WithAggregationShards(n int) Option {
+	return func(c *config) {
+		c.shards = max(n, 1)
+	}
+}
+
+// WithErrorHandler sets the function called with errors surfaced by the
+// statter. Errors raised by a reporter implementing [ErrorReporter] are
+// passed as a [*ReporterError].
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *config) {
+		c.errHandler = fn
+	}
+}
+
+// WithMaxSeries sets the maximum number of series held by a statter, across
+// all metric types. Once the limit is reached, new tag combinations are
+// redirected to an overflow series, see [Statter.DroppedSeries].
+// A limit of zero or less disables the limit.
+func WithMaxSeries(n int) Option {
+	return func(c *config) {
+		c.maxSeries = n
+	}
+}
+
+// WithMaxSeriesPerName sets the maximum number of series held by a statter
+// for a single metric name. Once the limit is reached, new tag combinations
+// for the name are redirected to an overflow series, see
+// [Statter.DroppedSeries]. A limit of zero or less disables the limit.
+func WithMaxSeriesPerName(n int) Option {
+	return func(c *config) {
+		c.maxNameSeries = n
+	}
+}
+
+// WithSeriesTTL sets the time after which a series that has not been
+// updated is removed from the statter, and from the reporter if it
+// supports removal. Expiry is checked on each flush, so series are
+// removed up to one interval after the TTL has passed. A TTL of zero
+// or less disables expiry.
+//
+// An expired series is removed as if deleted: a metric held by the caller,
+// such as a [*Counter] created once at start up, is no longer reported once
+// its series has expired, and updating it has no effect. With a TTL, fetch
+// metrics from the statter when used, or through a vector such as
+// [Statter.CounterVec], which re-creates expired series.
+func WithSeriesTTL(d time.Duration) Option {
+	return func(c *config) {
+		c.seriesTTL = d
+	}
+}
+
+// WithCallbackTimeout sets the maximum time a metric callback, such as
+// the function of a [GaugeFunc], may run. Callbacks running longer are
+// skipped for the report and a [*CallbackError] is passed to the error
+// handler. A timeout of zero or less disables the timeout.
+func WithCallbackTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.callbackTimeout = d
+	}
+}
+
+// WithDistributionBuckets sets the upper bounds of the buckets used to
+// count distributions locally, when the reporter does not implement
+// [DistributionReporter]. A +Inf bucket is always added.
+func WithDistributionBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.distBuckets = validBuckets(buckets)
+	}
+}
+
 // Statter collects and reports stats.
 type Statter struct {
 	reg    *registry
@@ -114,6 +248,9 @@ type Statter struct {
 // New returns a Statter that aggregates stats and flushes them to r on every
 // interval tick. Options may be used to set an initial prefix, tags, key
 // separator, and percentile configuration.
+//
+// If interval is zero or negative, no reporting loop is started and stats
+// are only reported when Flush or Close is called.
 func New(r Reporter, interval time.Duration, opts ...Option) *Statter {
 	cfg := defaultConfig()
 
@@ -121,6 +258,12 @@ func New(r Reporter, interval time.Duration, opts ...Option) *Statter {
 		opt(&cfg)
 	}
 
+	// Apply the tag policy to the initial tags, de-duplicating their keys.
+	if len(cfg.tags) > 0 {
+		cfg.tagPolicy.report(cfg.prefix, cfg.tags)
+		cfg.tags = mergeTags(make([]Tag, 0, len(cfg.tags)), cfg.tags, cfg.tagPolicy)
+	}
+
 	// Sort initial tags once to maintain the sorted-base-tags invariant,
 	// enabling zero-alloc fast paths in mergeDescriptors.
 	if len(cfg.tags) > 1 {
@@ -167,7 +310,7 @@ func (s *Statter) FullName(name string) string {
 
 // HasCounter determines if the counter exists.
 func (s *Statter) HasCounter(name string, tags ...Tag) bool {
-	k := s.key(name, tags)
+	k := s.seriesKey(name, tags)
 
 	_, ok := s.reg.counters.Load(k.String())
 
@@ -180,18 +323,30 @@ func (s *Statter) HasCounter(name string, tags ...Tag) bool {
 // created on the first call and the same instance is returned for subsequent
 // calls with identical name and tags.
 func (s *Statter) Counter(name string, tags ...Tag) *Counter {
+	return s.counter(name, tags, metricOptions{})
+}
+
+func (s *Statter) counter(name string, tags []Tag, opts metricOptions) *Counter {
+	if s.reg.closed.Load() {
+		return s.reg.nopCounter
+	}
+
 	k := s.key(name, tags)
 
-	c, ok := s.reg.counters.Load(k.String())
+	c, ok := loadSeries(s.reg, &s.reg.counters, k.String())
 	if !ok {
-		n, t := s.mergeDescriptors(name, tags)
-		counter := &Counter{
-			name: n,
-			tags: t,
-			key:  k.SafeString(),
-			reg:  s.reg,
-		}
-		c, _ = s.reg.counters.LoadOrStore(k.SafeString(), counter)
+		c, _ = newSeries(s, &s.reg.counters, k, name, tags, func(sr series) *Counter {
+			s.reg.describe(sr.name, opts.meta)
+			return &Counter{
+				name:     sr.name,
+				tags:     sr.tags,
+				key:      sr.key,
+				reg:      s.reg,
+				meta:     opts.meta,
+				overflow: sr.overflow,
+				rate:     opts.rate,
+			}
+		})
 	}
 
 	k.Release()
@@ -201,7 +356,7 @@ func (s *Statter) Counter(name string, tags ...Tag) *Counter {
 
 // HasGauge determines if the gauge exists.
 func (s *Statter) HasGauge(name string, tags ...Tag) bool {
-	k := s.key(name, tags)
+	k := s.seriesKey(name, tags)
 
 	_, ok := s.reg.gauges.Load(k.String())
 
@@ -214,18 +369,29 @@ func (s *Statter) HasGauge(name string, tags ...Tag) bool {
 // the first call and the same instance is returned for subsequent calls with
 // identical name and tags.
 func (s *Statter) Gauge(name string, tags ...Tag) *Gauge {
+	return s.gauge(name, tags, metricOptions{})
+}
+
+func (s *Statter) gauge(name string, tags []Tag, opts metricOptions) *Gauge {
+	if s.reg.closed.Load() {
+		return s.reg.nopGauge
+	}
+
 	k := s.key(name, tags)
 
-	g, ok := s.reg.gauges.Load(k.String())
+	g, ok := loadSeries(s.reg, &s.reg.gauges, k.String())
 	if !ok {
-		n, t := s.mergeDescriptors(name, tags)
-		gauge := &Gauge{
-			name: n,
-			tags: t,
-			key:  k.SafeString(),
-			reg:  s.reg,
-		}
-		g, _ = s.reg.gauges.LoadOrStore(k.SafeString(), gauge)
+		g, _ = newSeries(s, &s.reg.gauges, k, name, tags, func(sr series) *Gauge {
+			s.reg.describe(sr.name, opts.meta)
+			return &Gauge{
+				name:     sr.name,
+				tags:     sr.tags,
+				key:      sr.key,
+				reg:      s.reg,
+				meta:     opts.meta,
+				overflow: sr.overflow,
+			}
+		})
 	}
 
 	k.Release()
@@ -235,7 +401,7 @@ func (s *Statter) Gauge(name string, tags ...Tag) *Gauge {
 
 // HasHistogram determines if the histogram exists.
 func (s *Statter) HasHistogram(name string, tags ...Tag) bool {
-	k := s.key(name, tags)
+	k := s.seriesKey(name, tags)
 
 	_, ok := s.reg.histograms.Load(k.String())
 
@@ -253,15 +419,32 @@ func (s *Statter) HasHistogram(name string, tags ...Tag) bool {
 // each interval as a set of gauges (_sum, _mean, _stddev, _min, _max, and
 // each configured percentile) plus a _count counter.
 func (s *Statter) Histogram(name string, tags ...Tag) *Histogram {
+	return s.histogram(name, tags, metricOptions{})
+}
+
+func (s *Statter) histogram(name string, tags []Tag, opts metricOptions) *Histogram {
+	if s.reg.closed.Load() {
+		return s.reg.nopHistogram
+	}
+
 	k := s.key(name, tags)
 
-	h, ok := s.reg.histograms.Load(k.String())
+	h, ok := loadSeries(s.reg, &s.reg.histograms, k.String())
 	if !ok {
-		n, t := s.mergeDescriptors(name, tags)
-		histogram := newHistogram(s.reg.hr, n, t, s.reg.pool)
-		histogram.key = k.SafeString()
-		histogram.reg = s.reg
-		h, _ = s.reg.histograms.LoadOrStore(k.SafeString(), histogram)
+		h, _ = newSeries(s, &s.reg.histograms, k, name, tags, func(sr series) *Histogram {
+			s.reg.describe(sr.name, opts.meta)
+			histogram := newHistogram(s.reg.hr, s.reg.splitHist, sr.name, sr.tags, s.reg.newSample)
+			histogram.meta = opts.meta
+			histogram.key = sr.key
+			histogram.reg = s.reg
+			histogram.overflow = sr.overflow
+			if histogram.hrFn == nil {
+				// Delegated observations cannot be scaled,
+				// so only local aggregates are sampled.
+				histogram.rate = opts.rate
+			}
+			return histogram
+		})
 	}
 
 	k.Release()
@@ -271,7 +454,7 @@ func (s *Statter) Histogram(name string, tags ...Tag) *Histogram {
 
 // HasTiming determines if the timing exists.
 func (s *Statter) HasTiming(name string, tags ...Tag) bool {
-	k := s.key(name, tags)
+	k := s.seriesKey(name, tags)
 
 	_, ok := s.reg.timings.Load(k.String())
 
@@ -290,15 +473,32 @@ func (s *Statter) HasTiming(name string, tags ...Tag) bool {
 // (_sum_ms, _mean_ms, _stddev_ms, _min_ms, _max_ms, and each configured
 // percentile) plus a _count counter.
 func (s *Statter) Timing(name string, tags ...Tag) *Timing {
+	return s.timing(name, tags, metricOptions{})
+}
+
+func (s *Statter) timing(name string, tags []Tag, opts metricOptions) *Timing {
+	if s.reg.closed.Load() {
+		return s.reg.nopTiming
+	}
+
 	k := s.key(name, tags)
 
-	t, ok := s.reg.timings.Load(k.String())
+	t, ok := loadSeries(s.reg, &s.reg.timings, k.String())
 	if !ok {
-		n, tags := s.mergeDescriptors(name, tags)
-		timing := newTiming(s.reg.tr, n, tags, s.reg.pool)
-		timing.key = k.SafeString()
-		timing.reg = s.reg
-		t, _ = s.reg.timings.LoadOrStore(k.SafeString(), timing)
+		t, _ = newSeries(s, &s.reg.timings, k, name, tags, func(sr series) *Timing {
+			s.reg.describe(sr.name, opts.meta)
+			timing := newTiming(s.reg.tr, s.reg.splitTiming, sr.name, sr.tags, s.reg.newSample)
+			timing.meta = opts.meta
+			timing.key = sr.key
+			timing.reg = s.reg
+			timing.overflow = sr.overflow
+			if timing.trFn == nil {
+				// Delegated observations cannot be scaled,
+				// so only local aggregates are sampled.
+				timing.rate = opts.rate
+			}
+			return timing
+		})
 	}
 
 	k.Release()
@@ -306,6 +506,9 @@ func (s *Statter) Timing(name string, tags ...Tag) *Timing {
 	return t
 }
 
+// key returns the lookup key of a series, without applying the tag policy.
+// Series are stored under their series key, and lookup keys differing from
+// it are aliased to it when the series is first looked up.
 func (s *Statter) key(name string, tags []Tag) *key {
 	switch {
 	case s.prefix != "" && name != "":
@@ -316,54 +519,198 @@ func (s *Statter) key(name string, tags []Tag) *key {
 
 	keyTags := make([]Tag, len(s.tags), len(s.tags)+len(tags))
 	copy(keyTags, s.tags)
-	keyTags = mergeTags(keyTags, tags)
+	keyTags = mergeTags(keyTags, tags, nil)
 
 	return newKey(name, keyTags)
 }
 
+// seriesKey returns the key a series is stored under, with the tag
+// policy applied.
+func (s *Statter) seriesKey(name string, tags []Tag) *key {
+	if s.reg.cfg.tagPolicy == nil {
+		return s.key(name, tags)
+	}
+	return newKey(s.mergeDescriptors(name, tags))
+}
+
 func (s *Statter) mergeDescriptors(name string, tags []Tag) (string, []Tag) {
-	return mergeDescriptors(s.prefix, s.reg.cfg.separator, name, s.tags, tags)
+	return mergeDescriptors(s.prefix, s.reg.cfg.separator, name, s.tags, tags, s.reg.cfg.tagPolicy)
+}
+
+// series describes a new series.
+type series struct {
+	name     string
+	tags     []Tag
+	key      string
+	overflow bool
+}
+
+// loadSeries returns the series with key k from m, following the alias
+// of k if it has one.
+func loadSeries[M any](r *registry, m *hashtriemap.HashTrieMap[string, M], k string) (M, bool) {
+	v, ok := m.Load(k)
+	if ok || r.aliasCount.Load() == 0 {
+		return v, ok
+	}
+
+	if a, found := r.aliases.Load(k); found {
+		return m.Load(a.key)
+	}
+	return v, false
+}
+
+// newSeries returns the series with key k from m, creating it with create
+// if it does not exist. If a series limit has been reached, the overflow
+// series is returned instead, and k is aliased to it so later lookups
+// find it directly. It returns true if the series was created.
+//
+// The tag policy is only applied here, so that lookups of existing series
+// do not pay for it. When it changes the key of the series, k is aliased
+// to the series key.
+func newSeries[M any](s *Statter, m *hashtriemap.HashTrieMap[string, M], k *key, name string, tags []Tag, create func(series) M) (M, bool) {
+	n, t := s.mergeDescriptors(name, tags)
+	sr := series{name: n, tags: t, key: k.SafeString()}
+	var normalized string
+	if s.reg.cfg.tagPolicy != nil {
+		sk := newKey(n, t)
+		if sk.String() != k.String() {
+			normalized = sk.SafeString()
+			sr.key = normalized
+		}
+		sk.Release()
+	}
+	if normalized != "" {
+		a, ok := s.reg.aliases.Load(normalized)
+		if !ok {
+			a = seriesAlias{key: normalized}
+		}
+		if v, found := m.Load(a.key); found {
+			s.reg.alias(k.SafeString(), a)
+			return v, false
+		}
+	}
+
+	s.reg.cfg.tagPolicy.report(n, tags)
+	if !s.reg.admit(n) {
+		// The overflow series keeps the tag keys, so that reporters requiring
+		// a consistent set of tag keys per name (e.g. Prometheus) accept it.
+		overflowTags := make([]Tag, len(tags))
+		for i, tag := range tags {
+			overflowTags[i] = Tag{tag[0], overflowTagValue}
+		}
+		n, t = s.mergeDescriptors(name, overflowTags)
+
+		ok := newKey(n, t)
+		sr = series{name: n, tags: t, key: ok.SafeString(), overflow: true}
+		ok.Release()
+
+		a := seriesAlias{key: sr.key, name: n, overflow: true}
+		s.reg.alias(k.SafeString(), a)
+		if normalized != "" {
+			s.reg.alias(normalized, a)
+		}
+		if v, found := m.Load(sr.key); found {
+			return v, false
+		}
+	} else if normalized != "" {
+		s.reg.alias(k.SafeString(), seriesAlias{key: sr.key})
+	}
+
+	v, loaded := m.LoadOrStore(sr.key, create(sr))
+	s.reg.stored(sr.name, sr.tags, sr.overflow, loaded)
+	return v, !loaded
+}
+
+// DroppedSeries returns the number of series redirected to an overflow
+// series because a series limit was reached. Each dropped series is counted
+// once while its redirect is remembered. Up to 16384 redirects are kept, and
+// they are forgotten when a series is removed under the limit they reached,
+// so that dropped series may be admitted again.
+func (s *Statter) DroppedSeries() int64 {
+	return s.reg.dropped.Load()
+}
+
+// Flush synchronously reports all pending stats to the reporter. If a report
+// is already in progress, Flush waits for it to complete before reporting,
+// returning early with the context error if ctx is done first.
+//
+// Flush reports the stats of the whole statter tree and may be called on
+// any statter, including sub-statters.
+func (s *Statter) Flush(ctx context.Context) error {
+	return s.reg.Flush(ctx)
 }
 
 // Close stops the reporting loop, flushes any pending stats to the reporter,
 // and closes the reporter if it implements [io.Closer]. Close must be called
 // on the root statter; calling it on a sub-statter returns an error.
+//
+// Close is equivalent to calling CloseContext with a background context.
 func (s *Statter) Close() error {
-	if err := s.reg.Close(s); err != nil {
+	return s.CloseContext(context.Background())
+}
+
+// CloseContext stops the reporting loop, flushes any pending stats to the
+// reporter, and closes the reporter if it implements [io.Closer], giving up
+// once ctx is done. If a stage does not complete in time, a [*CloseError]
+// naming the stage is returned and the stage is left to complete in the
+// background; the reporter is not closed if the final flush timed out.
+//
+// Once CloseContext has been called the statter is closed, regardless of
+// the returned error. Metrics retrieved from a closed statter are no-ops,
+// metrics retrieved before no longer delegate to the reporter, and calling
+// Flush, Close or CloseContext again returns [ErrClosed].
+//
+// CloseContext must be called on the root statter; calling it on a
+// sub-statter returns an error.
+func (s *Statter) CloseContext(ctx context.Context) error {
+	if err := s.reg.Close(ctx, s); err != nil {
 		return err
 	}
 
-	if c, ok := s.reg.r.(io.Closer); ok {
-		if err := c.Close(); err != nil {
-			return err
-		}
+	c, ok := s.reg.r.(io.Closer)
+	if !ok {
+		return nil
 	}
-
-	return nil
+	return runContext(ctx, CloseStageReporter, c.Close)
 }
 
 // Counter implements a counter that monotonically accumulates a value between
 // flushes. The accumulated delta is reported and reset to zero on each flush.
 type Counter struct {
-	name string
-	tags [][2]string
-	key  string
-	reg  *registry
+	activity
+
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
+	rate     float64
 
 	val atomic.Int64
 }
 
 // Inc increments the counter by v.
 func (c *Counter) Inc(v int64) {
-	c.val.Add(v)
+	if sampled(c.rate) {
+		c.val.Add(v)
+	}
+	c.touch()
 }
 
-// Delete removes the counter.
+// Delete removes the counter. Delete is a no-op once the statter is closed.
 func (c *Counter) Delete() {
+	if c.reg.closed.Load() {
+		return
+	}
+
 	if rr, ok := c.reg.r.(RemovableReporter); ok {
 		rr.RemoveCounter(c.name, c.tags)
 	}
-	_, _ = c.reg.counters.LoadAndDelete(c.key)
+	if v, ok := c.reg.counters.LoadAndDelete(c.key); ok {
+		v.deleted.Store(true)
+		c.reg.removed(c.name, c.overflow)
+	}
 }
 
 func (c *Counter) value() int64 {
@@ -373,10 +720,14 @@ func (c *Counter) value() int64 {
 // Gauge implements a gauge that holds its last-set value and reports it on
 // each flush.
 type Gauge struct {
-	name string
-	tags [][2]string
-	key  string
-	reg  *registry
+	activity
+
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
 
 	val atomic.Uint64
 }
@@ -384,6 +735,7 @@ type Gauge struct {
 // Set sets the gauge value.
 func (g *Gauge) Set(v float64) {
 	g.val.Store(math.Float64bits(v))
+	g.touch()
 }
 
 // Inc increments the gauge by 1.
@@ -402,6 +754,7 @@ func (g *Gauge) Add(v float64) {
 		oldBits := g.val.Load()
 		newBits := math.Float64bits(math.Float64frombits(oldBits) + v)
 		if g.val.CompareAndSwap(oldBits, newBits) {
+			g.touch()
 			return
 		}
 	}
@@ -412,12 +765,19 @@ func (g *Gauge) Sub(v float64) {
 	g.Add(v * -1)
 }
 
-// Delete removes the gauge.
+// Delete removes the gauge. Delete is a no-op once the statter is closed.
 func (g *Gauge) Delete() {
+	if g.reg.closed.Load() {
+		return
+	}
+
 	if rr, ok := g.reg.r.(RemovableReporter); ok {
 		rr.RemoveGauge(g.name, g.tags)
 	}
-	_, _ = g.reg.gauges.LoadAndDelete(g.key)
+	if v, ok := g.reg.gauges.LoadAndDelete(g.key); ok {
+		v.deleted.Store(true)
+		g.reg.removed(g.name, g.overflow)
+	}
 }
 
 func (g *Gauge) value() float64 {
@@ -432,66 +792,88 @@ func (g *Gauge) value() float64 {
 // each interval as a set of gauges (_sum, _mean, _stddev, _min, _max, and
 // each configured percentile) plus a _count counter.
 type Histogram struct {
-	hrFn func(v float64)
-	name string
-	tags [][2]string
-	key  string
-	reg  *registry
-	pool *stats.Pool
-
-	mu sync.Mutex
-	s  *stats.Sample
-}
-
-func newHistogram(hr HistogramReporter, name string, tags [][2]string, pool *stats.Pool) *Histogram {
+	activity
+
+	hrFn     func(v float64)
+	exFn     func(v float64, exemplar [][2]string)
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
+	rate     float64
+
+	s *shardedSample
+}
+
+// newHistogram returns a histogram delegating to hr when it handles the
+// histogram, aggregating locally otherwise. If aggregate is set, the
+// histogram is aggregated locally even when delegated.
+func newHistogram(hr HistogramReporter, aggregate bool, name string, tags [][2]string, newSample func() *shardedSample) *Histogram {
+	h := &Histogram{
+		name: name,
+		tags: tags,
+	}
 	if hr != nil {
-		fn := hr.Histogram(name, tags)
-		if fn != nil {
-			return &Histogram{
-				hrFn: fn,
-				name: name,
-				tags: tags,
-			}
+		h.hrFn = hr.Histogram(name, tags)
+		if er, ok := hr.(ExemplarReporter); ok && h.hrFn != nil {
+			h.exFn = er.HistogramWithExemplar(name, tags)
 		}
 	}
-
-	return &Histogram{
-		name: name,
-		tags: tags,
-		pool: pool,
-		s:    pool.Get(),
+	if h.hrFn == nil || aggregate {
+		h.s = newSample()
 	}
+
+	return h
 }
 
-// Observe observes a histogram value.
+// Observe observes a histogram value. Values delegated to the reporter are
+// dropped once the statter is closed.
 func (h *Histogram) Observe(v float64) {
+	h.touch()
+
 	if h.hrFn != nil {
+		if h.reg.closed.Load() {
+			// The reporter may be closed.
+			return
+		}
 		h.hrFn(v)
+		if h.s == nil {
+			return
+		}
+	}
+
+	if !sampled(h.rate) {
 		return
 	}
 
-	h.mu.Lock()
 	h.s.Add(v)
-	h.mu.Unlock()
 }
 
-// Delete removes the histogram.
+// Delete removes the histogram. Delete is a no-op once the statter is closed.
 func (h *Histogram) Delete() {
-	if rtr, ok := h.reg.r.(RemovableHistogramReporter); ok {
-		rtr.RemoveHistogram(h.name, h.tags)
-	} else if rr, ok := h.reg.r.(RemovableReporter); ok {
+	if h.reg.closed.Load() {
+		return
+	}
+
+	if rhr, ok := h.reg.hr.(RemovableHistogramReporter); ok && h.hrFn != nil {
+		rhr.RemoveHistogram(h.name, h.tags)
+	}
+	if rr, ok := h.reg.ha.(RemovableReporter); ok && h.s != nil {
 		for _, k := range h.reg.sampleKeys(h.name, "") {
 			rr.RemoveGauge(k, h.tags)
 		}
 	}
-	_, _ = h.reg.histograms.LoadAndDelete(h.key)
+	if v, ok := h.reg.histograms.LoadAndDelete(h.key); ok {
+		v.deleted.Store(true)
+		h.reg.removed(h.name, h.overflow)
+	}
 }
 
 func (h *Histogram) value() *stats.Sample {
-	h.mu.Lock()
-	s := h.s
-	h.s = h.pool.Get()
-	h.mu.Unlock()
+	s := h.reg.pool.Get()
+	h.s.merge(s, true)
 
 	return s
 }
@@ -504,69 +886,91 @@ func (h *Histogram) value() *stats.Sample {
 // _stddev_ms, _min_ms, _max_ms, and each configured percentile) plus a
 // _count counter.
 type Timing struct {
-	trFn func(v time.Duration)
-	name string
-	tags [][2]string
-	key  string
-	reg  *registry
-	pool *stats.Pool
-
-	mu sync.Mutex
-	s  *stats.Sample
-}
-
-func newTiming(tr TimingReporter, name string, tags [][2]string, pool *stats.Pool) *Timing {
+	activity
+
+	trFn     func(v time.Duration)
+	exFn     func(v time.Duration, exemplar [][2]string)
+	name     string
+	tags     [][2]string
+	key      string
+	reg      *registry
+	meta     Metadata
+	overflow bool
+	rate     float64
+
+	s *shardedSample
+}
+
+// newTiming returns a timing delegating to tr when it handles the timing,
+// aggregating locally otherwise. If aggregate is set, the timing is
+// aggregated locally even when delegated.
+func newTiming(tr TimingReporter, aggregate bool, name string, tags [][2]string, newSample func() *shardedSample) *Timing {
+	t := &Timing{
+		name: name,
+		tags: tags,
+	}
 	if tr != nil {
-		fn := tr.Timing(name, tags)
-		if fn != nil {
-			return &Timing{
-				trFn: fn,
-				name: name,
-				tags: tags,
-			}
+		t.trFn = tr.Timing(name, tags)
+		if er, ok := tr.(ExemplarReporter); ok && t.trFn != nil {
+			t.exFn = er.TimingWithExemplar(name, tags)
 		}
 	}
-
-	return &Timing{
-		name: name,
-		tags: tags,
-		pool: pool,
-		s:    pool.Get(),
+	if t.trFn == nil || aggregate {
+		t.s = newSample()
 	}
+
+	return t
 }
 
 // Observe observes a timing duration.
 //
 // If the reporter does not handle timings, the duration
-// will be aggregated in milliseconds.
+// will be aggregated in milliseconds. Durations delegated to
+// the reporter are dropped once the statter is closed.
 func (t *Timing) Observe(d time.Duration) {
+	t.touch()
+
 	if t.trFn != nil {
+		if t.reg.closed.Load() {
+			// The reporter may be closed.
+			return
+		}
 		t.trFn(d)
+		if t.s == nil {
+			return
+		}
+	}
+
+	if !sampled(t.rate) {
 		return
 	}
 
-	t.mu.Lock()
 	t.s.Add(d.Seconds() * 1000)
-	t.mu.Unlock()
 }
 
-// Delete removes the timing.
+// Delete removes the timing. Delete is a no-op once the statter is closed.
 func (t *Timing) Delete() {
-	if rtr, ok := t.reg.r.(RemovableTimingReporter); ok {
+	if t.reg.closed.Load() {
+		return
+	}
+
+	if rtr, ok := t.reg.tr.(RemovableTimingReporter); ok && t.trFn != nil {
 		rtr.RemoveTiming(t.name, t.tags)
-	} else if rr, ok := t.reg.r.(RemovableReporter); ok {
+	}
+	if rr, ok := t.reg.ta.(RemovableReporter); ok && t.s != nil {
 		for _, k := range t.reg.sampleKeys(t.name, "_ms") {
 			rr.RemoveGauge(k, t.tags)
 		}
 	}
-	_, _ = t.reg.timings.LoadAndDelete(t.key)
+	if v, ok := t.reg.timings.LoadAndDelete(t.key); ok {
+		v.deleted.Store(true)
+		t.reg.removed(t.name, t.overflow)
+	}
 }
 
 func (t *Timing) value() *stats.Sample {
-	t.mu.Lock()
-	s := t.s
-	t.s = t.pool.Get()
-	t.mu.Unlock()
+	s := t.reg.pool.Get()
+	t.s.merge(s, true)
 
 	return s
 }
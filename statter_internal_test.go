@@ -1,9 +1,13 @@
 package statter
 
 import (
+	"context"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWithPrefix(t *testing.T) {
@@ -45,3 +49,138 @@ func TestWithPercentiles(t *testing.T) {
 
 	assert.Equal(t, []float64{1, 2, 3}, cfg.percentiles)
 }
+
+func TestWithAggregationShards(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithAggregationShards(4)(&cfg)
+
+	assert.Equal(t, 4, cfg.shards)
+
+	WithAggregationShards(0)(&cfg)
+
+	assert.Equal(t, 1, cfg.shards)
+}
+
+func TestWithMaxSeries(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithMaxSeries(10)(&cfg)
+
+	assert.Equal(t, 10, cfg.maxSeries)
+}
+
+func TestWithMaxSeriesPerName(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithMaxSeriesPerName(10)(&cfg)
+
+	assert.Equal(t, 10, cfg.maxNameSeries)
+}
+
+func TestWithSeriesTTL(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithSeriesTTL(time.Minute)(&cfg)
+
+	assert.Equal(t, time.Minute, cfg.seriesTTL)
+}
+
+func TestWithCallbackTimeout(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithCallbackTimeout(time.Minute)(&cfg)
+
+	assert.Equal(t, time.Minute, cfg.callbackTimeout)
+}
+
+func TestWithDistributionBuckets(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithDistributionBuckets([]float64{5, math.Inf(1), 1, math.NaN()})(&cfg)
+
+	assert.Equal(t, []float64{1, 5}, cfg.distBuckets)
+}
+
+func TestWithTraceIDExtractor(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithTraceIDExtractor(func(context.Context) string { return "abc" })(&cfg)
+
+	require.NotNil(t, cfg.traceIDFn)
+	assert.Equal(t, "abc", cfg.traceIDFn(t.Context()))
+}
+
+func TestWithAlignedFlush(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithAlignedFlush()(&cfg)
+
+	assert.True(t, cfg.alignFlush)
+}
+
+func TestWithFlushJitter(t *testing.T) {
+	cfg := defaultConfig()
+
+	WithFlushJitter(time.Second)(&cfg)
+
+	assert.Equal(t, time.Second, cfg.flushJitter)
+}
+
+func TestNextFlush(t *testing.T) {
+	last := time.Date(2024, 1, 1, 0, 0, 10, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "on schedule",
+			now:  last.Add(time.Second),
+			want: last.Add(10 * time.Second),
+		},
+		{
+			name: "on next boundary",
+			now:  last.Add(10 * time.Second),
+			want: last.Add(20 * time.Second),
+		},
+		{
+			name: "missed flushes",
+			now:  last.Add(25 * time.Second),
+			want: last.Add(30 * time.Second),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := nextFlush(last, test.now, 10*time.Second)
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	cfg := defaultConfig()
+	clock := &testClock{}
+
+	WithClock(clock)(&cfg)
+
+	assert.Same(t, clock, cfg.clock)
+}
+
+type testClock struct {
+	systemClock
+}
+
+func TestStatter_RemovalKeepsTagPolicyAliases(t *testing.T) {
+	s := New(DiscardReporter, 0, WithTagPolicy(TagPolicy{Lowercase: true}))
+	t.Cleanup(func() { _ = s.Close() })
+
+	c := s.Counter("test", Tag{"method", "GET"})
+	s.Counter("other").Delete()
+
+	assert.Equal(t, int64(1), s.reg.aliasCount.Load())
+	assert.Same(t, c, s.Counter("test", Tag{"method", "GET"}))
+}
@@ -1,7 +1,10 @@
 package statter_test
 
 import (
+	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -44,6 +47,69 @@ func TestStatter_CallsReporter(t *testing.T) {
 	m.AssertExpectations(t)
 }
 
+func TestStatter_Flush(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(2), [][2]string{{"tag", "test"}}).Once()
+	m.On("Counter", "test", int64(3), [][2]string{{"tag", "test"}}).Once()
+
+	stats := statter.New(m, 0)
+
+	stats.Counter("test", tags.Str("tag", "test")).Inc(2)
+
+	err := stats.Flush(t.Context())
+	require.NoError(t, err)
+
+	m.AssertCalled(t, "Counter", "test", int64(2), [][2]string{{"tag", "test"}})
+
+	stats.Counter("test", tags.Str("tag", "test")).Inc(3)
+
+	err = stats.With("sub").Flush(t.Context())
+	require.NoError(t, err)
+
+	err = stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_FlushCanceledContext(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.Test(t)
+
+	stats := statter.New(m, 0)
+
+	stats.Counter("test").Inc(2)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := stats.Flush(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	m.AssertNotCalled(t, "Counter", mock.Anything, mock.Anything, mock.Anything)
+
+	m.On("Counter", "test", int64(2), [][2]string{})
+	err = stats.Close()
+	require.NoError(t, err)
+}
+
+func TestStatter_ManualModeDoesNotReport(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.Test(t)
+
+	stats := statter.New(m, 0)
+
+	stats.Counter("test").Inc(2)
+
+	time.Sleep(10 * time.Millisecond)
+
+	m.AssertNotCalled(t, "Counter", mock.Anything, mock.Anything, mock.Anything)
+
+	m.On("Counter", "test", int64(2), [][2]string{})
+	err := stats.Close()
+	require.NoError(t, err)
+}
+
 func TestStatter_With(t *testing.T) {
 	m := &mockSimpleReporter{}
 	m.On("Counter", "prefix.prefix2.test", int64(2), [][2]string{{"base", "val"}, {"base2", "val2"}, {"tag", "test"}})
@@ -593,6 +659,278 @@ func TestStatter_CloseFromSubStatterFails(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestStatter_WithMaxSeries(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(1), [][2]string{{"id", "1"}}).Once()
+	m.On("Gauge", "other", 1.0, [][2]string{{"id", "overflow"}}).Once()
+	m.On("Counter", "test", int64(2), [][2]string{{"id", "overflow"}}).Once()
+
+	var errs []error
+	stats := statter.New(m, time.Second, statter.WithMaxSeries(1), statter.WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	stats.Counter("test", tags.Str("id", "1")).Inc(1)
+	stats.Counter("test", tags.Str("id", "2")).Inc(1)
+	stats.Counter("test", tags.Str("id", "3")).Inc(1)
+	stats.Gauge("other", tags.Str("id", "1")).Set(1)
+
+	assert.Equal(t, int64(3), stats.DroppedSeries())
+	assert.False(t, stats.HasCounter("test", tags.Str("id", "2")))
+	require.Len(t, errs, 2)
+	var limitErr *statter.SeriesLimitError
+	require.ErrorAs(t, errs[0], &limitErr)
+	assert.Equal(t, "test", limitErr.Name)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_WithMaxSeriesRemembersDroppedSeries(t *testing.T) {
+	m := &mockDescribingReporter{}
+	m.On("Describe", "test", statter.Metadata{Help: "help"}).Twice()
+	m.On("Counter", "test", int64(1), [][2]string{{"id", "1"}}).Once()
+	m.On("Counter", "test", int64(100), [][2]string{{"id", "overflow"}}).Once()
+
+	stats := statter.New(m, time.Second, statter.WithMaxSeries(1))
+
+	stats.Options(statter.Help("help")).Counter("test", tags.Str("id", "1")).Inc(1)
+	c := stats.Options(statter.Help("help")).Counter("test", tags.Str("id", "2"))
+	for range 99 {
+		assert.Same(t, c, stats.Options(statter.Help("help")).Counter("test", tags.Str("id", "2")))
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		stats.Options(statter.Help("help")).Counter("test", tags.Str("id", "2"))
+	})
+	c.Inc(100)
+
+	assert.Equal(t, int64(1), stats.DroppedSeries())
+	assert.Zero(t, allocs)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_WithMaxSeriesPerName(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(1), [][2]string{{"id", "1"}}).Once()
+	m.On("Counter", "test", int64(1), [][2]string{{"id", "overflow"}}).Once()
+	m.On("Counter", "other", int64(1), [][2]string{{"id", "1"}}).Once()
+
+	stats := statter.New(m, time.Second, statter.WithMaxSeriesPerName(1))
+
+	stats.Counter("test", tags.Str("id", "1")).Inc(1)
+	stats.Counter("test", tags.Str("id", "2")).Inc(1)
+	stats.Counter("other", tags.Str("id", "1")).Inc(1)
+
+	assert.Equal(t, int64(1), stats.DroppedSeries())
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_WithMaxSeriesReleasedOnDelete(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(1), [][2]string{{"id", "2"}}).Once()
+
+	stats := statter.New(m, time.Second, statter.WithMaxSeries(1))
+
+	stats.Counter("test", tags.Str("id", "1")).Delete()
+	stats.Counter("test", tags.Str("id", "2")).Inc(1)
+
+	assert.Equal(t, int64(0), stats.DroppedSeries())
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_WithMaxSeriesAdmitsDroppedSeriesOnDelete(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(1), [][2]string{{"id", "2"}}).Once()
+
+	stats := statter.New(m, time.Second, statter.WithMaxSeries(1))
+
+	c := stats.Counter("test", tags.Str("id", "1"))
+	stats.Counter("test", tags.Str("id", "2"))
+	c.Delete()
+	stats.Counter("test", tags.Str("id", "2")).Inc(1)
+
+	assert.Equal(t, int64(1), stats.DroppedSeries())
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_WithMaxSeriesPerNameKeepsRedirectsOfOtherNames(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(2), [][2]string{{"id", "overflow"}}).Once()
+
+	stats := statter.New(m, time.Second, statter.WithMaxSeriesPerName(1))
+
+	stats.Counter("test", tags.Str("id", "1"))
+	stats.Counter("test", tags.Str("id", "2")).Inc(1)
+	stats.Counter("other").Delete()
+	stats.Counter("test", tags.Str("id", "2")).Inc(1)
+
+	assert.Equal(t, int64(1), stats.DroppedSeries())
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_CloseContext(t *testing.T) {
+	m := &mockSimpleReporter{}
+	m.On("Counter", "test", int64(2), [][2]string{})
+
+	stats := statter.New(m, time.Second)
+
+	stats.Counter("test").Inc(2)
+
+	err := stats.CloseContext(t.Context())
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestStatter_CloseContextFlushTimeout(t *testing.T) {
+	r := &blockingReporter{block: make(chan struct{})}
+	t.Cleanup(func() { close(r.block) })
+
+	stats := statter.New(r, time.Second)
+
+	stats.Counter("test").Inc(2)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	t.Cleanup(cancel)
+
+	err := stats.CloseContext(ctx)
+
+	var closeErr *statter.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, statter.CloseStageFlush, closeErr.Stage)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, r.closed.Load())
+}
+
+func TestStatter_CloseContextReporterTimeout(t *testing.T) {
+	r := &blockingReporter{block: make(chan struct{})}
+	t.Cleanup(func() { close(r.block) })
+
+	stats := statter.New(r, time.Second)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	t.Cleanup(cancel)
+
+	err := stats.CloseContext(ctx)
+
+	var closeErr *statter.CloseError
+	require.ErrorAs(t, err, &closeErr)
+	assert.Equal(t, statter.CloseStageReporter, closeErr.Stage)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestStatter_CloseTwiceReturnsErrClosed(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, time.Second)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	err = stats.Close()
+	assert.ErrorIs(t, err, statter.ErrClosed)
+
+	err = stats.Flush(t.Context())
+	assert.ErrorIs(t, err, statter.ErrClosed)
+}
+
+func TestStatter_ClosedMetricsAreNoops(t *testing.T) {
+	m := &mockComplexReporter{}
+	m.Test(t)
+
+	stats := statter.New(m, time.Second)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	stats.Counter("test").Inc(1)
+	stats.Counter("test").Delete()
+	stats.Gauge("test").Set(1)
+	stats.Histogram("test").Observe(1)
+	stats.Timing("test").Observe(time.Second)
+
+	assert.False(t, stats.HasCounter("test"))
+	assert.False(t, stats.HasHistogram("test"))
+	m.AssertExpectations(t)
+}
+
+func TestStatter_HeldMetricsDoNotDelegateAfterClose(t *testing.T) {
+	m := &mockComplexReporter{}
+	var got []float64
+	m.On("Histogram", "test", [][2]string{}).Return(func(v float64) { got = append(got, v) })
+	m.On("Timing", "test", [][2]string{}).Return(func(d time.Duration) { got = append(got, d.Seconds()) })
+
+	stats := statter.New(m, time.Second)
+
+	h := stats.Histogram("test")
+	tm := stats.Timing("test")
+	h.Observe(1)
+	tm.Observe(2 * time.Second)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	h.Observe(3)
+	tm.Observe(4 * time.Second)
+
+	assert.Equal(t, []float64{1, 2}, got)
+	m.AssertExpectations(t)
+}
+
+func TestStatter_WithErrorHandler(t *testing.T) {
+	r := &errorReporter{err: errors.New("test error")}
+
+	var got error
+	stats := statter.New(r, time.Second, statter.WithErrorHandler(func(err error) {
+		got = err
+	}))
+
+	stats.Counter("test", tags.Str("tag", "test")).Inc(1)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	var repErr *statter.ReporterError
+	require.ErrorAs(t, got, &repErr)
+	assert.Same(t, r, repErr.Reporter)
+	assert.Equal(t, "test", repErr.Name)
+	assert.Equal(t, [][2]string{{"tag", "test"}}, repErr.Tags)
+	assert.ErrorIs(t, got, r.err)
+}
+
+func TestStatter_WithoutErrorHandlerDoesNotSetReporterHandler(t *testing.T) {
+	r := &errorReporter{err: errors.New("test error")}
+
+	stats := statter.New(r, time.Second)
+
+	stats.Counter("test").Inc(1)
+
+	err := stats.Close()
+	require.NoError(t, err)
+
+	assert.Nil(t, r.fn)
+}
+
 func TestNullReporter(t *testing.T) {
 	assert.Implements(t, (*statter.Reporter)(nil), statter.DiscardReporter)
 	assert.Implements(t, (*statter.HistogramReporter)(nil), statter.DiscardReporter)
@@ -663,6 +1001,46 @@ func (r *mockComplexReporter) RemoveTiming(name string, tags [][2]string) {
 	_ = r.Called(name, tags)
 }
 
+type errorReporter struct {
+	err error
+	fn  func(name string, tags [][2]string, err error)
+}
+
+func (r *errorReporter) OnError(fn func(name string, tags [][2]string, err error)) {
+	r.fn = fn
+}
+
+func (r *errorReporter) Counter(name string, _ int64, tags [][2]string) {
+	if r.fn != nil {
+		r.fn(name, tags, r.err)
+	}
+}
+
+func (r *errorReporter) Gauge(name string, _ float64, tags [][2]string) {
+	if r.fn != nil {
+		r.fn(name, tags, r.err)
+	}
+}
+
+type blockingReporter struct {
+	block  chan struct{}
+	closed atomic.Bool
+}
+
+func (r *blockingReporter) Counter(string, int64, [][2]string) {
+	<-r.block
+}
+
+func (r *blockingReporter) Gauge(string, float64, [][2]string) {
+	<-r.block
+}
+
+func (r *blockingReporter) Close() error {
+	<-r.block
+	r.closed.Store(true)
+	return nil
+}
+
 type waitingReporter struct {
 	mock.Mock
 
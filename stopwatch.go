@@ -0,0 +1,61 @@
+package statter
+
+import "time"
+
+// OutcomeTag is the tag key set by [Statter.Time] to the outcome
+// of the timed function.
+const OutcomeTag = "outcome"
+
+// Outcome tag values set by [Statter.Time].
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Stopwatch measures the duration from its start until it is stopped,
+// observing it on a timing.
+type Stopwatch struct {
+	t     *Timing
+	start time.Time
+}
+
+// Stop observes the duration since the stopwatch was started
+// and returns it.
+func (s Stopwatch) Stop() time.Duration {
+	d := s.t.reg.cfg.clock.Now().Sub(s.start)
+	s.t.Observe(d)
+	return d
+}
+
+// Start returns a stopwatch started at the current time.
+//
+//	defer stats.Timing("handler").Start().Stop()
+func (t *Timing) Start() Stopwatch {
+	return Stopwatch{t: t, start: t.reg.cfg.clock.Now()}
+}
+
+// ObserveSince observes the duration since t0.
+func (t *Timing) ObserveSince(t0 time.Time) {
+	t.Observe(t.reg.cfg.clock.Now().Sub(t0))
+}
+
+// Time calls fn and observes its duration on the timing with the given
+// name and tags, returning the error of fn. The timing is tagged with
+// [OutcomeTag], set to [OutcomeError] when fn returns an error and to
+// [OutcomeSuccess] otherwise.
+func (s *Statter) Time(name string, fn func() error, tags ...Tag) error {
+	clock := s.reg.cfg.clock
+	start := clock.Now()
+	err := fn()
+	d := clock.Now().Sub(start)
+
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	// Limit the capacity so the caller's tags are never overwritten.
+	tags = append(tags[:len(tags):len(tags)], Tag{OutcomeTag, outcome})
+	s.Timing(name, tags...).Observe(d)
+
+	return err
+}
@@ -0,0 +1,60 @@
+package statter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiming_Start(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	sw := stats.Timing("test", tags.Str("tag", "test")).Start()
+	time.Sleep(time.Millisecond)
+	got := sw.Stop()
+
+	assert.GreaterOrEqual(t, got, time.Millisecond)
+	r.AssertTiming(t, "test", []statter.Tag{tags.Str("tag", "test")}, got)
+}
+
+func TestTiming_ObserveSince(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	stats.Timing("test").ObserveSince(time.Now().Add(-time.Second))
+
+	got := r.TimingValues("test", nil)
+	require.Len(t, got, 1)
+	assert.GreaterOrEqual(t, got[0], time.Second)
+}
+
+func TestStatter_Time(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	err := stats.Time("test", func() error { return nil }, tags.Str("tag", "test"))
+	require.NoError(t, err)
+
+	testErr := errors.New("test error")
+	err = stats.Time("test", func() error { return testErr }, tags.Str("tag", "test"))
+	assert.ErrorIs(t, err, testErr)
+
+	assert.Len(t, r.TimingValues("test", []statter.Tag{tags.Str("tag", "test"), {statter.OutcomeTag, statter.OutcomeSuccess}}), 1)
+	assert.Len(t, r.TimingValues("test", []statter.Tag{tags.Str("tag", "test"), {statter.OutcomeTag, statter.OutcomeError}}), 1)
+}
+
+func TestStatter_TimeDoesNotModifyTags(t *testing.T) {
+	stats, _ := statstest.NewStatter(t)
+
+	ts := make([]statter.Tag, 1, 2)
+	ts[0] = tags.Str("tag", "test")
+	ts2 := append(ts, tags.Str("other", "test"))
+
+	_ = stats.Time("test", func() error { return nil }, ts...)
+
+	assert.Equal(t, tags.Str("other", "test"), ts2[1])
+}
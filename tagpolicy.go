@@ -0,0 +1,178 @@
+package statter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// OtherTagValue is the value replacing tag values not allowed by a
+// [TagPolicy].
+const OtherTagValue = "other"
+
+// TagPolicy restricts and normalizes the tags of all metrics of a statter,
+// see [WithTagPolicy].
+//
+// Tag values are first made valid UTF-8, replacing invalid bytes with the
+// Unicode replacement character, then lower-cased, checked against the
+// allowed values and finally truncated.
+type TagPolicy struct {
+	// AllowedKeys restricts the tag keys to the given keys, dropping
+	// the tags with other keys. All keys are allowed if empty.
+	AllowedKeys []string
+
+	// AllowedValues restricts the values of the given tag keys, replacing
+	// other values with [OtherTagValue].
+	AllowedValues map[string][]string
+
+	// Lowercase lower-cases tag values.
+	Lowercase bool
+
+	// MaxValueLength truncates tag values to the given length in bytes,
+	// on a rune boundary. Values are not truncated if zero.
+	MaxValueLength int
+
+	// OnViolation is called with each tag violating the policy, once
+	// for each series or sub-statter created with it.
+	OnViolation func(v TagViolation)
+}
+
+// TagViolationReason is the reason a tag violated a [TagPolicy].
+type TagViolationReason string
+
+// Tag violation reasons.
+const (
+	TagKeyNotAllowed    TagViolationReason = "key not allowed"
+	TagValueNotAllowed  TagViolationReason = "value not allowed"
+	TagValueInvalidUTF8 TagViolationReason = "value not valid UTF-8"
+	TagValueTruncated   TagViolationReason = "value truncated"
+)
+
+// TagViolation describes a tag violating a [TagPolicy].
+type TagViolation struct {
+	// Name is the name of the metric or the prefix of the sub-statter.
+	Name   string
+	Tag    Tag
+	Reason TagViolationReason
+}
+
+// WithTagPolicy sets the policy applied to the tags of all metrics, so that
+// every reporter receives restricted and normalized tags. Series whose tags
+// are identical once the policy is applied are the same series.
+//
+// The policy is applied when a series is first looked up with given tags,
+// and the tags are remembered to resolve to that series, so later lookups
+// do not pay for the policy. Up to 16384 such tags are remembered, shared
+// with the redirects of [Statter.DroppedSeries].
+func WithTagPolicy(p TagPolicy) Option {
+	return func(c *config) {
+		c.tagPolicy = newTagPolicy(p)
+	}
+}
+
+type tagViolations uint8
+
+const (
+	violationKey tagViolations = 1 << iota
+	violationValue
+	violationUTF8
+	violationLength
+)
+
+var violationReasons = []struct {
+	v      tagViolations
+	reason TagViolationReason
+}{
+	{violationKey, TagKeyNotAllowed},
+	{violationUTF8, TagValueInvalidUTF8},
+	{violationValue, TagValueNotAllowed},
+	{violationLength, TagValueTruncated},
+}
+
+type tagPolicy struct {
+	keys        map[string]struct{}
+	values      map[string]map[string]struct{}
+	lower       bool
+	maxLen      int
+	onViolation func(v TagViolation)
+}
+
+func newTagPolicy(p TagPolicy) *tagPolicy {
+	tp := &tagPolicy{
+		lower:       p.Lowercase,
+		maxLen:      p.MaxValueLength,
+		onViolation: p.OnViolation,
+	}
+	if len(p.AllowedKeys) > 0 {
+		tp.keys = make(map[string]struct{}, len(p.AllowedKeys))
+		for _, k := range p.AllowedKeys {
+			tp.keys[k] = struct{}{}
+		}
+	}
+	if len(p.AllowedValues) > 0 {
+		tp.values = make(map[string]map[string]struct{}, len(p.AllowedValues))
+		for k, vals := range p.AllowedValues {
+			set := make(map[string]struct{}, len(vals))
+			for _, v := range vals {
+				set[v] = struct{}{}
+			}
+			tp.values[k] = set
+		}
+	}
+	return tp
+}
+
+// apply returns the tag with the policy applied and its violations.
+// The tag must be dropped if its key is not allowed.
+func (p *tagPolicy) apply(tag Tag) (Tag, tagViolations) {
+	if p.keys != nil {
+		if _, ok := p.keys[tag[0]]; !ok {
+			return tag, violationKey
+		}
+	}
+
+	var v tagViolations
+	val := tag[1]
+	if !utf8.ValidString(val) {
+		val = strings.ToValidUTF8(val, string(utf8.RuneError))
+		v |= violationUTF8
+	}
+	if p.lower {
+		val = strings.ToLower(val)
+	}
+	if allowed, ok := p.values[tag[0]]; ok {
+		if _, ok = allowed[val]; !ok {
+			val = OtherTagValue
+			v |= violationValue
+		}
+	}
+	if p.maxLen > 0 && len(val) > p.maxLen {
+		val = truncate(val, p.maxLen)
+		v |= violationLength
+	}
+
+	return Tag{tag[0], val}, v
+}
+
+// report reports the policy violations of tags for name.
+func (p *tagPolicy) report(name string, tags []Tag) {
+	if p == nil || p.onViolation == nil {
+		return
+	}
+
+	for _, tag := range tags {
+		_, v := p.apply(tag)
+		for _, r := range violationReasons {
+			if v&r.v != 0 {
+				p.onViolation(TagViolation{Name: name, Tag: tag, Reason: r.reason})
+			}
+		}
+	}
+}
+
+// truncate truncates s to at most n bytes on a rune boundary.
+func truncate(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
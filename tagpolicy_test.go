@@ -0,0 +1,104 @@
+package statter_test
+
+import (
+	"testing"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTagPolicy(t *testing.T) {
+	var got []statter.TagViolation
+	stats, r := statstest.NewStatter(t, statter.WithTagPolicy(statter.TagPolicy{
+		AllowedKeys:    []string{"method", "path"},
+		AllowedValues:  map[string][]string{"method": {"get", "post"}},
+		Lowercase:      true,
+		MaxValueLength: 5,
+		OnViolation: func(v statter.TagViolation) {
+			got = append(got, v)
+		},
+	}))
+
+	c := stats.Counter("test", tags.Str("method", "PATCH"), tags.Str("path", "/users"), tags.Str("user", "1"))
+	c.Inc(1)
+	stats.Counter("test", tags.Str("method", "PATCH"), tags.Str("path", "/users"), tags.Str("user", "2")).Inc(1)
+	r.Flush(t)
+
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("method", "other"), tags.Str("path", "/user")}, 2)
+	assert.Equal(t, []statter.TagViolation{
+		{Name: "test", Tag: tags.Str("method", "PATCH"), Reason: statter.TagValueNotAllowed},
+		{Name: "test", Tag: tags.Str("path", "/users"), Reason: statter.TagValueTruncated},
+		{Name: "test", Tag: tags.Str("user", "1"), Reason: statter.TagKeyNotAllowed},
+	}, got)
+}
+
+func TestWithTagPolicy_ReplacesInvalidUTF8(t *testing.T) {
+	var got []statter.TagViolation
+	stats, r := statstest.NewStatter(t, statter.WithTagPolicy(statter.TagPolicy{
+		OnViolation: func(v statter.TagViolation) {
+			got = append(got, v)
+		},
+	}))
+
+	stats.Gauge("test", tags.Str("foo", "a\xffb")).Set(1)
+	r.Flush(t)
+
+	r.AssertGauge(t, "test", []statter.Tag{tags.Str("foo", "a�b")}, 1)
+	assert.Equal(t, []statter.TagViolation{
+		{Name: "test", Tag: tags.Str("foo", "a\xffb"), Reason: statter.TagValueInvalidUTF8},
+	}, got)
+}
+
+func TestWithTagPolicy_TruncatesOnRuneBoundary(t *testing.T) {
+	stats, r := statstest.NewStatter(t, statter.WithTagPolicy(statter.TagPolicy{MaxValueLength: 4}))
+
+	stats.Counter("test", tags.Str("foo", "abcé")).Inc(1)
+	r.Flush(t)
+
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("foo", "abc")}, 1)
+}
+
+func TestWithTagPolicy_AppliesToSubStatters(t *testing.T) {
+	var got []statter.TagViolation
+	stats, r := statstest.NewStatter(t,
+		statter.WithTags(tags.Str("env", "PROD"), tags.Str("host", "a")),
+		statter.WithTagPolicy(statter.TagPolicy{
+			AllowedKeys: []string{"env", "region"},
+			Lowercase:   true,
+			OnViolation: func(v statter.TagViolation) {
+				got = append(got, v)
+			},
+		}),
+	)
+
+	sub := stats.With("sub", tags.Str("region", "EU"), tags.Str("zone", "1"))
+
+	assert.Same(t, sub, stats.With("sub", tags.Str("region", "eu")))
+
+	sub.Counter("test").Inc(1)
+	r.Flush(t)
+
+	r.AssertCounter(t, "sub.test", []statter.Tag{tags.Str("env", "prod"), tags.Str("region", "eu")}, 1)
+	assert.Equal(t, []statter.TagViolation{
+		{Name: "", Tag: tags.Str("host", "a"), Reason: statter.TagKeyNotAllowed},
+		{Name: "sub", Tag: tags.Str("zone", "1"), Reason: statter.TagKeyNotAllowed},
+	}, got)
+}
+
+func TestWithTagPolicy_ResolvesLookupsToNormalizedSeries(t *testing.T) {
+	stats, r := statstest.NewStatter(t, statter.WithTagPolicy(statter.TagPolicy{Lowercase: true}))
+
+	c := stats.Counter("test", tags.Str("method", "GET"))
+	c.Inc(1)
+	stats.Counter("test", tags.Str("method", "Get")).Inc(1)
+
+	assert.Same(t, c, stats.Counter("test", tags.Str("method", "get")))
+	assert.True(t, stats.HasCounter("test", tags.Str("method", "GET")))
+	allocs := testing.AllocsPerRun(100, func() {
+		stats.Counter("test", tags.Str("method", "GET"))
+	})
+	assert.Zero(t, allocs)
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("method", "get")}, 2)
+}
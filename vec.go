@@ -0,0 +1,263 @@
+package statter
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+	"slices"
+	"sync/atomic"
+
+	"github.com/go4org/hashtriemap"
+)
+
+// CounterVec is a vector of counters sharing a name and tag keys,
+// see [Statter.CounterVec].
+type CounterVec struct {
+	vec[*Counter]
+}
+
+// CounterVec returns a vector of counters with the given name and tag keys.
+//
+// The counters of the vector are resolved from their tag values with
+// [CounterVec.With], which skips building and sorting the metric key of
+// [Statter.Counter]. A vector should be created once and kept, as it caches
+// the counters it resolves.
+func (s *Statter) CounterVec(name string, keys ...string) *CounterVec {
+	return &CounterVec{vec: newVec(s, name, keys, s.Counter, s.HasCounter)}
+}
+
+// With returns the counter with the given tag values, in the order of the
+// tag keys of the vector. The counter may be kept by the caller, until it
+// is deleted or expires.
+func (v *CounterVec) With(values ...string) *Counter {
+	return v.with(values)
+}
+
+// Delete deletes the counter with the given tag values.
+func (v *CounterVec) Delete(values ...string) {
+	if c, ok := v.remove(values); ok {
+		c.Delete()
+	}
+}
+
+// GaugeVec is a vector of gauges sharing a name and tag keys,
+// see [Statter.GaugeVec].
+type GaugeVec struct {
+	vec[*Gauge]
+}
+
+// GaugeVec returns a vector of gauges with the given name and tag keys.
+//
+// The gauges of the vector are resolved from their tag values with
+// [GaugeVec.With], which skips building and sorting the metric key of
+// [Statter.Gauge]. A vector should be created once and kept, as it caches
+// the gauges it resolves.
+func (s *Statter) GaugeVec(name string, keys ...string) *GaugeVec {
+	return &GaugeVec{vec: newVec(s, name, keys, s.Gauge, s.HasGauge)}
+}
+
+// With returns the gauge with the given tag values, in the order of the
+// tag keys of the vector. The gauge may be kept by the caller, until it
+// is deleted or expires.
+func (v *GaugeVec) With(values ...string) *Gauge {
+	return v.with(values)
+}
+
+// Delete deletes the gauge with the given tag values.
+func (v *GaugeVec) Delete(values ...string) {
+	if g, ok := v.remove(values); ok {
+		g.Delete()
+	}
+}
+
+// HistogramVec is a vector of histograms sharing a name and tag keys,
+// see [Statter.HistogramVec].
+type HistogramVec struct {
+	vec[*Histogram]
+}
+
+// HistogramVec returns a vector of histograms with the given name and
+// tag keys.
+//
+// The histograms of the vector are resolved from their tag values with
+// [HistogramVec.With], which skips building and sorting the metric key of
+// [Statter.Histogram]. A vector should be created once and kept, as it
+// caches the histograms it resolves.
+func (s *Statter) HistogramVec(name string, keys ...string) *HistogramVec {
+	return &HistogramVec{vec: newVec(s, name, keys, s.Histogram, s.HasHistogram)}
+}
+
+// With returns the histogram with the given tag values, in the order of
+// the tag keys of the vector. The histogram may be kept by the caller,
+// until it is deleted or expires.
+func (v *HistogramVec) With(values ...string) *Histogram {
+	return v.with(values)
+}
+
+// Delete deletes the histogram with the given tag values.
+func (v *HistogramVec) Delete(values ...string) {
+	if h, ok := v.remove(values); ok {
+		h.Delete()
+	}
+}
+
+// TimingVec is a vector of timings sharing a name and tag keys,
+// see [Statter.TimingVec].
+type TimingVec struct {
+	vec[*Timing]
+}
+
+// TimingVec returns a vector of timings with the given name and tag keys.
+//
+// The timings of the vector are resolved from their tag values with
+// [TimingVec.With], which skips building and sorting the metric key of
+// [Statter.Timing]. A vector should be created once and kept, as it caches
+// the timings it resolves.
+func (s *Statter) TimingVec(name string, keys ...string) *TimingVec {
+	return &TimingVec{vec: newVec(s, name, keys, s.Timing, s.HasTiming)}
+}
+
+// With returns the timing with the given tag values, in the order of the
+// tag keys of the vector. The timing may be kept by the caller, until it
+// is deleted or expires.
+func (v *TimingVec) With(values ...string) *Timing {
+	return v.with(values)
+}
+
+// Delete deletes the timing with the given tag values.
+func (v *TimingVec) Delete(values ...string) {
+	if t, ok := v.remove(values); ok {
+		t.Delete()
+	}
+}
+
+// minVecSweep is the minimum number of children of a vector
+// before the children of deleted series are swept.
+const minVecSweep = 64
+
+// vecMetric is a metric held by a vector.
+type vecMetric interface {
+	isDeleted() bool
+	isOverflow() bool
+}
+
+func (c *Counter) isOverflow() bool   { return c.overflow }
+func (g *Gauge) isOverflow() bool     { return g.overflow }
+func (h *Histogram) isOverflow() bool { return h.overflow }
+func (t *Timing) isOverflow() bool    { return t.overflow }
+
+// vec caches the metrics of a vector by a hash of their tag values.
+//
+// Overflow series are not cached, and the children of deleted or expired
+// series are swept as the vector grows, so that the children stay bounded
+// by the series held by the statter.
+type vec[M vecMetric] struct {
+	s    *Statter
+	name string
+	keys []string
+	get  func(name string, tags ...Tag) M
+	has  func(name string, tags ...Tag) bool
+	seed maphash.Seed
+
+	children hashtriemap.HashTrieMap[uint64, *vecChild[M]]
+	size     atomic.Int64
+	sweepAt  atomic.Int64
+}
+
+type vecChild[M vecMetric] struct {
+	values []string
+	m      M
+}
+
+func newVec[M vecMetric](s *Statter, name string, keys []string, get func(string, ...Tag) M, has func(string, ...Tag) bool) vec[M] {
+	return vec[M]{
+		s:    s,
+		name: name,
+		keys: slices.Clone(keys),
+		get:  get,
+		has:  has,
+		seed: maphash.MakeSeed(),
+	}
+}
+
+func (v *vec[M]) with(values []string) M {
+	h := v.hash(values)
+	if c, ok := v.children.Load(h); ok && !c.m.isDeleted() && slices.Equal(c.values, values) {
+		return c.m
+	}
+
+	m := v.get(v.name, v.tags(values)...)
+	if m.isOverflow() {
+		return m
+	}
+
+	// A colliding or deleted child is replaced, and
+	// resolved again through the statter when needed.
+	if _, loaded := v.children.Swap(h, &vecChild[M]{values: slices.Clone(values), m: m}); !loaded {
+		if at := v.sweepAt.Load(); v.size.Add(1) >= at && v.sweepAt.CompareAndSwap(at, math.MaxInt64) {
+			v.sweep()
+		}
+	}
+	return m
+}
+
+// sweep removes the children of deleted series, such as expired series,
+// and schedules the next sweep once the vector has doubled in size.
+func (v *vec[M]) sweep() {
+	var n int64
+	v.children.Range(func(h uint64, c *vecChild[M]) bool {
+		if !c.m.isDeleted() {
+			n++
+			return true
+		}
+		if v.children.CompareAndDelete(h, c) {
+			v.size.Add(-1)
+		}
+		return true
+	})
+	v.sweepAt.Store(max(2*n, minVecSweep))
+}
+
+// remove removes the child with the given values from the vector,
+// returning its metric if it exists in the statter.
+func (v *vec[M]) remove(values []string) (M, bool) {
+	h := v.hash(values)
+	if c, ok := v.children.Load(h); ok && slices.Equal(c.values, values) && v.children.CompareAndDelete(h, c) {
+		v.size.Add(-1)
+	}
+
+	tags := v.tags(values)
+	if !v.has(v.name, tags...) {
+		var m M
+		return m, false
+	}
+	return v.get(v.name, tags...), true
+}
+
+func (v *vec[M]) hash(values []string) uint64 {
+	var h maphash.Hash
+	h.SetSeed(v.seed)
+	for _, val := range values {
+		_, _ = h.WriteString(val)
+		_ = h.WriteByte(0)
+	}
+	return h.Sum64()
+}
+
+// tags pairs the tag keys of the vector with values. Missing values are
+// left empty, and extra values are dropped.
+func (v *vec[M]) tags(values []string) []Tag {
+	if len(values) != len(v.keys) {
+		v.s.reg.handleError(fmt.Errorf("%w: %s has %d tag keys, got %d values",
+			ErrTagValues, v.s.FullName(v.name), len(v.keys), len(values)))
+	}
+
+	tags := make([]Tag, len(v.keys))
+	for i, k := range v.keys {
+		tags[i][0] = k
+		if i < len(values) {
+			tags[i][1] = values[i]
+		}
+	}
+	return tags
+}
@@ -0,0 +1,53 @@
+package statter
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVec_DoesNotCacheOverflowSeries(t *testing.T) {
+	s := New(DiscardReporter, 0, WithMaxSeries(10))
+	t.Cleanup(func() { _ = s.Close() })
+
+	v := s.CounterVec("test", "id")
+	for i := range 1000 {
+		v.With(strconv.Itoa(i)).Inc(1)
+	}
+
+	assert.Equal(t, int64(10), v.size.Load())
+	assert.Equal(t, 10, countChildren(&v.vec))
+}
+
+func TestVec_SweepsExpiredChildren(t *testing.T) {
+	s := New(DiscardReporter, 0, WithSeriesTTL(time.Minute))
+	t.Cleanup(func() { _ = s.Close() })
+
+	v := s.CounterVec("test", "id")
+	for i := range 100 {
+		v.With(strconv.Itoa(i))
+	}
+	now := time.Now()
+	s.reg.expire(now)
+	s.reg.expire(now.Add(2 * time.Minute))
+	require.False(t, s.HasCounter("test", Tag{"id", "0"}))
+
+	for i := range 28 {
+		v.With("new" + strconv.Itoa(i))
+	}
+
+	assert.Equal(t, int64(28), v.size.Load())
+	assert.Equal(t, 28, countChildren(&v.vec))
+}
+
+func countChildren[M vecMetric](v *vec[M]) int {
+	var n int
+	v.children.Range(func(uint64, *vecChild[M]) bool {
+		n++
+		return true
+	})
+	return n
+}
@@ -0,0 +1,125 @@
+package statter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamba/statter/v2"
+	"github.com/hamba/statter/v2/statstest"
+	"github.com/hamba/statter/v2/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatter_CounterVec(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	v := stats.CounterVec("test", "method", "code")
+	v.With("GET", "200").Inc(1)
+	v.With("GET", "200").Inc(2)
+	v.With("POST", "500").Inc(1)
+	r.Flush(t)
+
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("code", "200"), tags.Str("method", "GET")}, 3)
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("method", "POST"), tags.Str("code", "500")}, 1)
+}
+
+func TestStatter_CounterVecReturnsIdenticalCounter(t *testing.T) {
+	stats := statter.New(statter.DiscardReporter, 0)
+	t.Cleanup(func() { _ = stats.Close() })
+
+	v := stats.CounterVec("test", "method")
+
+	assert.Same(t, v.With("GET"), v.With("GET"))
+	assert.Same(t, stats.Counter("test", tags.Str("method", "GET")), v.With("GET"))
+	assert.NotSame(t, v.With("GET"), v.With("POST"))
+}
+
+func TestStatter_CounterVecWithSubStatter(t *testing.T) {
+	stats, r := statstest.NewStatter(t, statter.WithTags(tags.Str("env", "prod")))
+
+	stats.With("http").CounterVec("requests", "method").With("GET").Inc(1)
+	r.Flush(t)
+
+	r.AssertCounter(t, "http.requests", []statter.Tag{tags.Str("env", "prod"), tags.Str("method", "GET")}, 1)
+}
+
+func TestStatter_CounterVecDelete(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	v := stats.CounterVec("test", "method")
+	c := v.With("GET")
+	c.Inc(1)
+
+	v.Delete("GET")
+
+	assert.False(t, stats.HasCounter("test", tags.Str("method", "GET")))
+
+	v.With("GET").Inc(2)
+	r.Flush(t)
+
+	assert.NotSame(t, c, v.With("GET"))
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("method", "GET")}, 2)
+}
+
+func TestStatter_CounterVecRecreatesDeletedCounter(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	v := stats.CounterVec("test", "method")
+	c := v.With("GET")
+	c.Delete()
+
+	v.With("GET").Inc(1)
+	r.Flush(t)
+
+	assert.NotSame(t, c, v.With("GET"))
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("method", "GET")}, 1)
+}
+
+func TestStatter_CounterVecHandlesMismatchedValues(t *testing.T) {
+	var got error
+	stats, r := statstest.NewStatter(t, statter.WithErrorHandler(func(err error) { got = err }))
+
+	stats.CounterVec("test", "method", "code").With("GET").Inc(1)
+	r.Flush(t)
+
+	require.Error(t, got)
+	assert.True(t, errors.Is(got, statter.ErrTagValues))
+	r.AssertCounter(t, "test", []statter.Tag{tags.Str("method", "GET"), tags.Str("code", "")}, 1)
+}
+
+func TestStatter_GaugeVec(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	v := stats.GaugeVec("test", "queue")
+	v.With("a").Set(1)
+	v.With("b").Set(2)
+	r.Flush(t)
+
+	r.AssertGauge(t, "test", []statter.Tag{tags.Str("queue", "a")}, 1)
+	r.AssertGauge(t, "test", []statter.Tag{tags.Str("queue", "b")}, 2)
+}
+
+func TestStatter_HistogramVec(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	v := stats.HistogramVec("test", "queue")
+	v.With("a").Observe(1)
+	v.With("a").Observe(2)
+
+	r.AssertHistogram(t, "test", []statter.Tag{tags.Str("queue", "a")}, 1, 2)
+
+	v.Delete("a")
+
+	assert.False(t, stats.HasHistogram("test", tags.Str("queue", "a")))
+}
+
+func TestStatter_TimingVec(t *testing.T) {
+	stats, r := statstest.NewStatter(t)
+
+	v := stats.TimingVec("test", "queue")
+	v.With("a").Observe(time.Second)
+
+	r.AssertTiming(t, "test", []statter.Tag{tags.Str("queue", "a")}, time.Second)
+}